@@ -0,0 +1,158 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"cdr.dev/slog/v3/internal/fallback"
+)
+
+var (
+	defaultLogger  atomic.Pointer[Logger]
+	fallbackLogger atomic.Pointer[Logger]
+)
+
+// Default returns the process wide default Logger used by
+// the package level logging functions.
+//
+// Until SetDefault is called, it is a Logger that writes to
+// os.Stderr at LevelInfo in the format of sloghuman if the
+// program imports it or else in a plain one line format with
+// the fields encoded as JSON. Entries are never discarded.
+func Default() Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return *l
+	}
+	if l := fallbackLogger.Load(); l != nil {
+		return *l
+	}
+	s, ok := fallback.Sink().(Sink)
+	if !ok {
+		// sloghuman may not be initialized yet
+		// so the stderr Logger is not cached.
+		return stderrLogger
+	}
+	l := Make(s)
+	fallbackLogger.Store(&l)
+	return l
+}
+
+// SetDefault atomically replaces the default Logger.
+// It is safe to call concurrently with logging.
+func SetDefault(l Logger) {
+	defaultLogger.Store(&l)
+}
+
+// Debug logs the msg and fields at LevelDebug with the default Logger.
+func Debug(ctx context.Context, msg string, fields ...Field) {
+	Default().log(ctx, LevelDebug, msg, fields)
+}
+
+// Info logs the msg and fields at LevelInfo with the default Logger.
+func Info(ctx context.Context, msg string, fields ...Field) {
+	Default().log(ctx, LevelInfo, msg, fields)
+}
+
+// Warn logs the msg and fields at LevelWarn with the default Logger.
+func Warn(ctx context.Context, msg string, fields ...Field) {
+	Default().log(ctx, LevelWarn, msg, fields)
+}
+
+// There is no package level Error function as Error creates the
+// error field. Use Log with LevelError or Default().Error instead.
+
+// Critical logs the msg and fields at LevelCritical with the default Logger.
+//
+// It will then Sync().
+func Critical(ctx context.Context, msg string, fields ...Field) {
+	l := Default()
+	l.log(ctx, LevelCritical, msg, fields)
+	l.Sync()
+}
+
+// Fatal logs the msg and fields at LevelFatal with the default Logger.
+//
+// It will then Sync(), run the hooks registered with
+// RegisterExitHook and exit like Logger.Fatal.
+func Fatal(ctx context.Context, msg string, fields ...Field) {
+	l := Default()
+	l.log(ctx, LevelFatal, msg, fields)
+	l.Sync()
+	l.exitWith(ctx, 1)
+}
+
+// Log logs the msg and fields at level with the default Logger.
+//
+// It will Sync() at LevelError and above but never exits.
+func Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	l := Default()
+	l.log(ctx, level, msg, fields)
+	if level >= LevelError {
+		l.Sync()
+	}
+}
+
+var stderrLogger = Make(newWriterSink(os.Stderr))
+
+// writerSink is the sink of the default Logger in programs
+// that do not import sloghuman. It writes every entry on one
+// line such as:
+//
+//	2000-02-05 04:04:04.000 [INFO] <main.go:12> server: started {"port":80}
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) LogEntry(_ context.Context, ent SinkEntry) {
+	var b bytes.Buffer
+	b.WriteString(ent.Time.Format("2006-01-02 15:04:05.000"))
+	b.WriteString(" [" + ent.Level.String() + "] ")
+	if ent.File != "" {
+		b.WriteString("<" + filepath.Base(ent.File) + ":" + strconv.Itoa(ent.Line) + "> ")
+	}
+	for i, name := range ent.LoggerNames {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(name)
+	}
+	if len(ent.LoggerNames) > 0 {
+		b.WriteString(": ")
+	}
+	b.WriteString(ent.Message)
+	if len(ent.Fields) > 0 {
+		// Map.MarshalJSON redacts the values and encodes
+		// errors so nothing is lost or leaked.
+		fields, err := json.Marshal(ent.Fields)
+		if err != nil {
+			fields = []byte(strconv.Quote(err.Error()))
+		}
+		b.WriteByte(' ')
+		b.Write(fields)
+	}
+	b.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.w.Write(b.Bytes())
+}
+
+func (s *writerSink) Sync() {
+	if f, ok := s.w.(interface{ Sync() error }); ok {
+		// Syncing a terminal or pipe fails and
+		// there is nowhere to report it anyway.
+		_ = f.Sync()
+	}
+}
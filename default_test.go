@@ -0,0 +1,67 @@
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func TestDefault(t *testing.T) {
+	// This can't be parallel since it modifies the default Logger.
+
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	assert.True(t, "sloghuman fallback", prev.Enabled(bg, slog.LevelInfo))
+
+	s := &fakeSink{}
+	slog.SetDefault(slog.Make(s).Named("default"))
+
+	slog.Debug(bg, "dropped")
+	slog.Info(bg, "info")
+	slog.Warn(bg, "warn")
+	slog.Log(bg, slog.LevelError, "error")
+	slog.Critical(bg, "critical")
+	slog.FromContext(bg).Info(bg, "from context")
+
+	assert.Len(t, "entries", 5, s.entries)
+	assert.Equal(t, "syncs", 2, s.syncs)
+	assert.Equal(t, "names", []string{"default"}, s.entries[0].LoggerNames)
+	assert.Equal(t, "level", slog.LevelError, s.entries[2].Level)
+	for _, e := range s.entries {
+		assert.Equal(t, "file", slogTestFile[:len(slogTestFile)-len("slog_test.go")]+"default_test.go", e.File)
+		assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestDefault", e.Func)
+	}
+	assert.Equal(t, "line", 23, s.entries[0].Line)
+}
+
+func TestWriterSink(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	s := slog.NewWriterSink(b)
+	s.LogEntry(bg, slog.SinkEntry{
+		Time:        time.Date(2000, time.February, 5, 4, 4, 4, 4e6, time.UTC),
+		Level:       slog.LevelWarn,
+		Message:     "disk full",
+		LoggerNames: []string{"server", "disk"},
+		File:        "/src/main.go",
+		Line:        12,
+		Fields: slog.M(
+			slog.F("free", 0),
+			slog.F("api_token", "secret"),
+		),
+	})
+	s.LogEntry(bg, slog.SinkEntry{
+		Time:    time.Date(2000, time.February, 5, 4, 4, 4, 0, time.UTC),
+		Level:   slog.LevelInfo,
+		Message: "started",
+	})
+	s.Sync()
+
+	assert.Equal(t, "output", `2000-02-05 04:04:04.004 [WARN] <main.go:12> server.disk: disk full {"free":0,"api_token":"[REDACTED]"}
+2000-02-05 04:04:04.000 [INFO] started
+`, b.String())
+}
@@ -0,0 +1,136 @@
+package slog
+
+import "strconv"
+
+// DuplicateFields is the policy for fields with the same name
+// in an entry such as a context field and a field passed to
+// a logging call.
+//
+// Regardless of the policy, Map.MarshalJSON never writes
+// duplicate keys. It suffixes the names of the duplicates
+// like DuplicateFieldsSuffix.
+type DuplicateFields int
+
+const (
+	// DuplicateFieldsKeepAll keeps all the fields.
+	// This is the default.
+	DuplicateFieldsKeepAll DuplicateFields = iota
+	// DuplicateFieldsLastWins keeps only the last field
+	// with a name.
+	DuplicateFieldsLastWins
+	// DuplicateFieldsFirstWins keeps only the first field
+	// with a name.
+	DuplicateFieldsFirstWins
+	// DuplicateFieldsSuffix renames the second field with
+	// a name such as user to user#2, the third to user#3
+	// and so on.
+	DuplicateFieldsSuffix
+)
+
+// WithDuplicateFields returns a Logger that applies the policy p
+// to the fields of every entry once the context fields, the
+// fields added with With, the fields of the logging call and
+// those added by hooks have been merged. It is applied inside
+// groups as well.
+func (l Logger) WithDuplicateFields(p DuplicateFields) Logger {
+	l.duplicates = p
+	return l
+}
+
+// apply returns m with p applied or m itself if it has
+// no duplicates.
+func (p DuplicateFields) apply(m Map) Map {
+	if p == DuplicateFieldsKeepAll {
+		return m
+	}
+
+	var m2 Map
+	for i, f := range m {
+		v := f.Value
+		if gm, ok := v.(Map); ok {
+			v = p.apply(gm)
+		}
+
+		drop := false
+		switch p {
+		case DuplicateFieldsLastWins:
+			drop = indexOfName(m[i+1:], f.Name) >= 0
+		case DuplicateFieldsFirstWins:
+			drop = indexOfName(m[:i], f.Name) >= 0
+		}
+
+		if m2 == nil {
+			if !drop && sameValue(f.Value, v) {
+				continue
+			}
+			m2 = make(Map, i, len(m))
+			copy(m2, m[:i])
+		}
+		if !drop {
+			m2 = append(m2, F(f.Name, v))
+		}
+	}
+	if m2 != nil {
+		m = m2
+	}
+	if p == DuplicateFieldsSuffix {
+		m = suffixDuplicates(m)
+	}
+	return m
+}
+
+// suffixDuplicates returns m with the names of the fields
+// whose name is already used by a previous field suffixed
+// with #2, #3 and so on. It returns m itself if all names
+// are unique.
+func suffixDuplicates(m Map) Map {
+	if !hasDuplicates(m) {
+		return m
+	}
+
+	var m2 Map
+	for i, f := range m {
+		if m2 == nil {
+			if indexOfName(m[:i], f.Name) < 0 {
+				continue
+			}
+			m2 = make(Map, i, len(m))
+			copy(m2, m[:i])
+		}
+		name := f.Name
+		// The new name must not be used by any other field.
+		for n := 2; indexOfName(m2, name) >= 0 || (name != f.Name && indexOfName(m[i+1:], name) >= 0); n++ {
+			name = f.Name + "#" + strconv.Itoa(n)
+		}
+		m2 = append(m2, F(name, f.Value))
+	}
+	return m2
+}
+
+func hasDuplicates(m Map) bool {
+	if len(m) <= 16 {
+		for i, f := range m {
+			if indexOfName(m[:i], f.Name) >= 0 {
+				return true
+			}
+		}
+		return false
+	}
+	names := make(map[string]struct{}, len(m))
+	for _, f := range m {
+		if _, ok := names[f.Name]; ok {
+			return true
+		}
+		names[f.Name] = struct{}{}
+	}
+	return false
+}
+
+func indexOfName(m Map, name string) int {
+	for i, f := range m {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
+}
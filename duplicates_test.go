@@ -0,0 +1,99 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func TestDuplicateFields(t *testing.T) {
+	t.Parallel()
+
+	log := func(p slog.DuplicateFields) slog.Map {
+		s := &fakeSink{}
+		l := slog.Make(s).WithDuplicateFields(p).With(slog.F("user", "with"), slog.F("id", 1))
+		ctx := slog.With(bg, slog.F("user", "ctx"))
+		l.Info(ctx, "hi", slog.F("user", "call"), slog.Group("g", slog.F("a", 1), slog.F("a", 2)))
+		return s.entries[0].Fields
+	}
+
+	t.Run("keepAll", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fields", slog.M(
+			slog.F("user", "with"),
+			slog.F("id", 1),
+			slog.F("user", "ctx"),
+			slog.F("user", "call"),
+			slog.Group("g", slog.F("a", 1), slog.F("a", 2)),
+		), log(slog.DuplicateFieldsKeepAll))
+	})
+
+	t.Run("lastWins", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fields", slog.M(
+			slog.F("id", 1),
+			slog.F("user", "call"),
+			slog.Group("g", slog.F("a", 2)),
+		), log(slog.DuplicateFieldsLastWins))
+	})
+
+	t.Run("firstWins", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fields", slog.M(
+			slog.F("user", "with"),
+			slog.F("id", 1),
+			slog.Group("g", slog.F("a", 1)),
+		), log(slog.DuplicateFieldsFirstWins))
+	})
+
+	t.Run("suffix", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fields", slog.M(
+			slog.F("user", "with"),
+			slog.F("id", 1),
+			slog.F("user#2", "ctx"),
+			slog.F("user#3", "call"),
+			slog.Group("g", slog.F("a", 1), slog.F("a#2", 2)),
+		), log(slog.DuplicateFieldsSuffix))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "JSON", indentJSON(t, `{
+			"user": 1,
+			"user#3": 2,
+			"user#2": 3,
+			"user#4": 4
+		}`), marshalJSON(t, slog.M(
+			slog.F("user", 1),
+			slog.F("user", 2),
+			slog.F("user#2", 3),
+			slog.F("user", 4),
+		)))
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).WithDuplicateFields(slog.DuplicateFieldsLastWins).AppendHooks(
+			slog.HookFunc(func(_ context.Context, e slog.SinkEntry) (slog.SinkEntry, bool) {
+				e.Fields = append(e.Fields, slog.F("tenant", "hook"))
+				return e, true
+			}),
+		)
+		l.Info(bg, "hi", slog.F("tenant", "call"), slog.F("id", 1))
+
+		assert.Equal(t, "fields", slog.M(
+			slog.F("id", 1),
+			slog.F("tenant", "hook"),
+		), s.entries[0].Fields)
+	})
+}
@@ -0,0 +1,41 @@
+package slog
+
+import "cdr.dev/slog/v3/internal/errchain"
+
+// LogFielder is implemented by errors that carry structured
+// context such as the ID of a user or an HTTP status.
+//
+// The fields of every error in the chain of an error field,
+// including the errors wrapped with fmt.Errorf, xerrors.Errorf
+// and errors.Join, are written by all bundled sinks. JSON sinks
+// add them to the object of the error in the chain while the
+// human readable sinks add them to the entry prefixed with the
+// name of the error field such as error.user_id.
+type LogFielder interface {
+	LogFields() []Field
+}
+
+// ErrorFields returns the fields of the errors in the chain
+// of err that implement LogFielder, the outermost first.
+func ErrorFields(err error) Map {
+	var m Map
+	for err != nil {
+		m = append(m, logFields(err)...)
+
+		next, joined := errchain.Unwrap(err)
+		for _, e := range joined {
+			m = append(m, ErrorFields(e)...)
+		}
+		err = next
+	}
+	return m
+}
+
+// logFields returns the fields of err itself.
+func logFields(err error) Map {
+	lf, ok := err.(LogFielder)
+	if !ok {
+		return nil
+	}
+	return lf.LogFields()
+}
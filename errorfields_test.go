@@ -0,0 +1,133 @@
+package slog_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+type notFoundError struct {
+	user int
+}
+
+func (e notFoundError) Error() string {
+	return "user not found"
+}
+
+func (e notFoundError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("user_id", e.user)}
+}
+
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("status %d: %v", e.status, e.err)
+}
+
+func (e statusError) Unwrap() error {
+	return e.err
+}
+
+func (e statusError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("status", e.status)}
+}
+
+func TestErrorFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chain", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("get user: %w", statusError{
+			status: 404,
+			err:    xerrors.Errorf("query: %w", notFoundError{user: 42}),
+		})
+		assert.Equal(t, "fields", slog.M(
+			slog.F("status", 404),
+			slog.F("user_id", 42),
+		), slog.ErrorFields(err))
+	})
+
+	t.Run("joined", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.Join(notFoundError{user: 1}, errors.New("eof"), notFoundError{user: 2})
+		assert.Equal(t, "fields", slog.M(
+			slog.F("user_id", 1),
+			slog.F("user_id", 2),
+		), slog.ErrorFields(err))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fields", slog.Map(nil), slog.ErrorFields(fmt.Errorf("wrap: %w", errors.New("eof"))))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		exp := indentJSON(t, `{
+			"error": [
+				{
+					"msg": "get user",
+					"type": "*fmt.wrapError"
+				},
+				{
+					"msg": "status 404",
+					"type": "slog_test.statusError",
+					"fields": {
+						"status": 404
+					}
+				},
+				{
+					"msg": "user not found",
+					"type": "slog_test.notFoundError",
+					"fields": {
+						"user_id": 42
+					}
+				}
+			]
+		}`)
+		err := fmt.Errorf("get user: %w", statusError{
+			status: 404,
+			err:    notFoundError{user: 42},
+		})
+		assert.Equal(t, "JSON", exp, marshalJSON(t, slog.M(slog.Error(err))))
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		t.Parallel()
+
+		exp := indentJSON(t, `{
+			"error": [
+				{
+					"msg": "unauthorized",
+					"type": "slog_test.tokenError",
+					"fields": {
+						"api_token": "[REDACTED]"
+					}
+				}
+			]
+		}`)
+		assert.Equal(t, "JSON", exp, marshalJSON(t, slog.M(slog.Error(tokenError{}))))
+	})
+}
+
+type tokenError struct{}
+
+func (tokenError) Error() string {
+	return "unauthorized"
+}
+
+func (tokenError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("api_token", "secret")}
+}
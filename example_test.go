@@ -131,3 +131,15 @@ func ExampleLogger_Leveled() {
 	// 2019-12-07 21:26:20.945 [INFO]	received request
 	// 2019-12-07 21:26:20.945 [DEBU]	testing2
 }
+
+func ExampleFromContext() {
+	ctx := context.Background()
+
+	l := slog.Make(sloghuman.Sink(os.Stdout)).Named("http")
+	ctx = slog.IntoContext(ctx, l)
+
+	// Deep inside a library with only ctx available.
+	slog.FromContext(ctx).Info(ctx, "received request")
+
+	// 2019-12-07 21:20:56.974 [info]  http: received request
+}
@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultExitTimeout is the default time the exit hooks
+// are given to run before the process exits.
+const DefaultExitTimeout = 5 * time.Second
+
+var exitHooks = struct {
+	mu      sync.Mutex
+	hooks   []*exitHook
+	timeout time.Duration
+}{
+	timeout: DefaultExitTimeout,
+}
+
+type exitHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// RegisterExitHook registers fn to be run by Fatal before the
+// process exits, such as to flush metrics or close database
+// connections that deferred calls would have cleaned up.
+//
+// Hooks run one at a time in the reverse order they were
+// registered in, like deferred calls. They share the timeout
+// set with SetExitTimeout and the context passed to them is
+// canceled once it expires. Fatal does not wait for hooks that
+// are still running after the timeout. Hooks that fail or time
+// out are logged with their name at LevelError.
+//
+// The hooks only run once per process even if Fatal is called
+// concurrently. They are not run by Loggers with an exit function
+// set with WithExit as those do not exit the process.
+// The returned function unregisters the hook.
+func RegisterExitHook(name string, fn func(ctx context.Context) error) (unregister func()) {
+	h := &exitHook{
+		name: name,
+		fn:   fn,
+	}
+
+	exitHooks.mu.Lock()
+	exitHooks.hooks = append(exitHooks.hooks, h)
+	exitHooks.mu.Unlock()
+
+	return func() {
+		exitHooks.mu.Lock()
+		defer exitHooks.mu.Unlock()
+		for i, h2 := range exitHooks.hooks {
+			if h2 == h {
+				exitHooks.hooks = append(exitHooks.hooks[:i:i], exitHooks.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetExitTimeout sets the total time the exit hooks are
+// given to run. It defaults to DefaultExitTimeout.
+func SetExitTimeout(d time.Duration) {
+	exitHooks.mu.Lock()
+	exitHooks.timeout = d
+	exitHooks.mu.Unlock()
+}
+
+// WithExit returns a Logger that calls fn instead of os.Exit
+// once Fatal has logged the entry.
+//
+// The hooks registered with RegisterExitHook are not run so that
+// a test calling Fatal does not shut down the resources of the
+// process. fn may panic or return to let the caller of Fatal
+// continue, which is useful in tests.
+func (l Logger) WithExit(fn func(code int)) Logger {
+	l.exit = fn
+	return l
+}
+
+func (l Logger) exitWith(ctx context.Context, code int) {
+	if l.exit != nil {
+		l.exit(code)
+		return
+	}
+	l.runExitHooks(ctx)
+	defaultExitFn(code)
+}
+
+// runExitHooks runs and removes the registered exit hooks.
+func (l Logger) runExitHooks(ctx context.Context) {
+	exitHooks.mu.Lock()
+	hooks := exitHooks.hooks
+	exitHooks.hooks = nil
+	timeout := exitHooks.timeout
+	exitHooks.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	// ctx may already be canceled so the hooks get a fresh context.
+	hctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	running := make(chan string, len(hooks))
+	go func() {
+		defer close(done)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			h := hooks[i]
+			running <- h.name
+			err := h.fn(hctx)
+			if err != nil {
+				l.Error(ctx, "exit hook failed",
+					F("hook", h.name),
+					Error(err),
+				)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-hctx.Done():
+		select {
+		case <-done:
+			return
+		default:
+		}
+		var name string
+		for len(running) > 0 {
+			name = <-running
+		}
+		l.Error(ctx, "exit hooks timed out",
+			F("hook", name),
+			F("timeout", timeout),
+		)
+	}
+}
@@ -0,0 +1,108 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+// The exit hook tests can't be parallel since the hooks are global.
+
+func TestExitHooks(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		var calls []string
+		hook := func(name string, err error) func(context.Context) error {
+			return func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.True(t, "deadline", ok)
+				calls = append(calls, name)
+				return err
+			}
+		}
+		defer slog.RegisterExitHook("db", hook("db", nil))()
+		defer slog.RegisterExitHook("metrics", hook("metrics", errors.New("flush failed")))()
+		slog.RegisterExitHook("removed", hook("removed", nil))()
+
+		s := &fakeSink{}
+		var code int
+		defer slog.SetDefaultExitFn(func(c int) {
+			code = c
+		})()
+		l := slog.Make(s)
+		l.Fatal(bg, "bye")
+
+		assert.Equal(t, "calls", []string{"metrics", "db"}, calls)
+		assert.Equal(t, "code", 1, code)
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Equal(t, "msg", "bye", s.entries[0].Message)
+		assert.Equal(t, "msg", "exit hook failed", s.entries[1].Message)
+		assert.Equal(t, "hook", slog.F("hook", "metrics"), s.entries[1].Fields[0])
+
+		// The hooks only run once.
+		l.Fatal(bg, "bye again")
+		assert.Len(t, "calls", 2, calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		slog.SetExitTimeout(10 * time.Millisecond)
+		defer slog.SetExitTimeout(slog.DefaultExitTimeout)
+
+		release := make(chan struct{})
+		defer close(release)
+		defer slog.RegisterExitHook("stuck", func(ctx context.Context) error {
+			<-release
+			return nil
+		})()
+
+		s := &fakeSink{}
+		exited := false
+		defer slog.SetDefaultExitFn(func(int) {
+			exited = true
+		})()
+		l := slog.Make(s)
+		l.Fatal(bg, "bye")
+
+		assert.True(t, "exited", exited)
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Equal(t, "msg", "exit hooks timed out", s.entries[1].Message)
+		assert.Equal(t, "hook", slog.F("hook", "stuck"), s.entries[1].Fields[0])
+	})
+
+	t.Run("withExit", func(t *testing.T) {
+		called := false
+		defer slog.RegisterExitHook("db", func(context.Context) error {
+			called = true
+			return nil
+		})()
+
+		exited := false
+		l := slog.Make(&fakeSink{}).WithExit(func(int) {
+			exited = true
+		})
+		l.Fatal(bg, "bye")
+
+		assert.True(t, "exited", exited)
+		assert.False(t, "hook called", called)
+	})
+}
+
+func TestWithExit(t *testing.T) {
+	t.Parallel()
+
+	l := slog.Make(&fakeSink{}).WithExit(func(code int) {
+		panic(code)
+	})
+
+	var r interface{}
+	func() {
+		defer func() {
+			r = recover()
+		}()
+		l.Fatal(bg, "bye")
+	}()
+	assert.Equal(t, "panic", 1, r)
+}
@@ -1,5 +1,13 @@
 package slog
 
-func (l *Logger) SetExit(fn func(int)) {
-	l.exit = fn
+var NewWriterSink = newWriterSink
+
+// SetDefaultExitFn replaces os.Exit for the Loggers without
+// an exit function until the returned function is called.
+func SetDefaultExitFn(fn func(int)) (restore func()) {
+	prev := defaultExitFn
+	defaultExitFn = fn
+	return func() {
+		defaultExitFn = prev
+	}
 }
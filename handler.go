@@ -0,0 +1,117 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler returns a log/slog Handler that logs through l.
+//
+// This allows code written against the standard library's log/slog
+// package to share the sinks, names, fields and level of l.
+//
+// log/slog levels are mapped onto the closest builtin or registered
+// Level at or below them. slog.LevelError+4 and slog.LevelError+8 map
+// to LevelCritical and LevelFatal but the Handler never exits the process.
+//
+// Groups are mapped onto Logger.WithGroup and Group fields and the
+// source location is taken from the record's PC.
+func Handler(l Logger) stdslog.Handler {
+	return handler{l: l}
+}
+
+type handler struct {
+	l Logger
+}
+
+var _ stdslog.Handler = handler{}
+
+func (h handler) Enabled(ctx context.Context, level stdslog.Level) bool {
+	return h.l.Enabled(ctx, levelFromStdlib(level))
+}
+
+func (h handler) Handle(ctx context.Context, r stdslog.Record) error {
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	var fields Map
+	r.Attrs(func(a stdslog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+
+	ent := SinkEntry{
+		Time:        t.UTC(),
+		Level:       levelFromStdlib(r.Level),
+		Message:     r.Message,
+		Fields:      fieldsFromContext(ctx).append(h.l.groups.nest(fields)),
+		SpanContext: trace.SpanContextFromContext(ctx),
+	}
+	if !h.l.enabled(ctx, ent.Level, nil) {
+		return nil
+	}
+	if r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent = ent.fillFromFrame(f)
+	}
+	// The stack starts at the caller of log/slog.
+	if st := h.l.stacks.fields(ent.Level, 1, ent.Func); len(st) > 0 {
+		ent.Fields = ent.Fields.append(st)
+	}
+
+	h.l.logEntry(ctx, ent)
+	return nil
+}
+
+func (h handler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	var fields Map
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	h.l = h.l.With(fields...)
+	return h
+}
+
+func (h handler) WithGroup(name string) stdslog.Handler {
+	h.l = h.l.WithGroup(name)
+	return h
+}
+
+// appendAttr appends the fields for a to m.
+func appendAttr(m Map, a stdslog.Attr) Map {
+	a.Value = a.Value.Resolve()
+	if a.Equal(stdslog.Attr{}) {
+		return m
+	}
+
+	if a.Value.Kind() != stdslog.KindGroup {
+		return append(m, F(a.Key, a.Value.Any()))
+	}
+
+	var fields Map
+	for _, ga := range a.Value.Group() {
+		fields = appendAttr(fields, ga)
+	}
+	if len(fields) == 0 {
+		return m
+	}
+	if a.Key == "" {
+		// Groups without a key are inlined.
+		return append(m, fields...)
+	}
+	return append(m, Group(a.Key, fields...))
+}
+
+// levelFromStdlib maps level onto the closest known Level at or below it.
+// log/slog levels are 4 apart while Levels are 10 apart.
+func levelFromStdlib(level stdslog.Level) Level {
+	return floorLevel(LevelInfo + Level(level)*(LevelWarn-LevelInfo)/4)
+}
@@ -0,0 +1,153 @@
+//go:build go1.21
+
+package slog_test
+
+import (
+	"bytes"
+	stdslog "log/slog"
+	"testing"
+	"time"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/internal/entryhuman"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("basic", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).Named("svc").With(slog.F("with", 1))
+		ctx := slog.With(bg, slog.F("ctx", 2))
+
+		sl := stdslog.New(slog.Handler(l))
+		sl.DebugContext(ctx, "dropped")
+		sl.InfoContext(ctx, "hello", "a", 3, stdslog.Duration("d", time.Second))
+
+		assert.Len(t, "entries", 1, s.entries)
+		ent := s.entries[0]
+		assert.Equal(t, "level", slog.LevelInfo, ent.Level)
+		assert.Equal(t, "msg", "hello", ent.Message)
+		assert.Equal(t, "names", []string{"svc"}, ent.LoggerNames)
+		assert.Equal(t, "file", slogTestFile[:len(slogTestFile)-len("slog_test.go")]+"handler_test.go", ent.File)
+		assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestHandler.func1", ent.Func)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("with", 1),
+			slog.F("ctx", 2),
+			slog.F("a", int64(3)),
+			slog.F("d", time.Second),
+		), ent.Fields)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		sl := stdslog.New(slog.Handler(slog.Make(s)))
+		sl = sl.With("top", 1).WithGroup("db").With("name", "pg").WithGroup("empty")
+		sl.Info("query", stdslog.Group("q", "sql", "select 1"), stdslog.Group("", "inline", true), stdslog.Attr{})
+		sl.Info("no attrs")
+
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("top", int64(1)),
+			slog.F("db", slog.M(
+				slog.F("name", "pg"),
+				slog.F("empty", slog.M(
+					slog.F("q", slog.M(
+						slog.F("sql", "select 1"),
+					)),
+					slog.F("inline", true),
+				)),
+			)),
+		), s.entries[0].Fields)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("top", int64(1)),
+			slog.F("db", slog.M(
+				slog.F("name", "pg"),
+			)),
+		), s.entries[1].Fields)
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		h := slog.Handler(slog.Make(s).Leveled(slog.LevelDebug))
+		sl := stdslog.New(h)
+		sl.Debug("")
+		sl.Info("")
+		sl.Warn("")
+		sl.Error("")
+		sl.Log(bg, stdslog.LevelError+4, "")
+		sl.Log(bg, stdslog.LevelError+8, "")
+
+		assert.True(t, "debug enabled", h.Enabled(bg, stdslog.LevelDebug))
+		assert.Len(t, "entries", 6, s.entries)
+		assert.Equal(t, "level", slog.LevelDebug, s.entries[0].Level)
+		assert.Equal(t, "level", slog.LevelInfo, s.entries[1].Level)
+		assert.Equal(t, "level", slog.LevelWarn, s.entries[2].Level)
+		assert.Equal(t, "level", slog.LevelError, s.entries[3].Level)
+		assert.Equal(t, "level", slog.LevelCritical, s.entries[4].Level)
+		assert.Equal(t, "level", slog.LevelFatal, s.entries[5].Level)
+	})
+
+	t.Run("stackTraces", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).StackTraces(slog.LevelWarn).AllStackTraces(slog.LevelError)
+		sl := stdslog.New(slog.Handler(l))
+		sl.Info("no stack")
+		sl.Warn("stack")
+		sl.Error("goroutines")
+
+		assert.Len(t, "entries", 3, s.entries)
+		assert.Len(t, "fields", 0, s.entries[0].Fields)
+		assert.Len(t, "fields", 1, s.entries[1].Fields)
+		st, ok := s.entries[1].Fields[0].Value.(slog.Stack)
+		assert.True(t, "stack", ok)
+		assert.Equal(t, "func", s.entries[1].Func, st.Frames[0].Func)
+		assert.Equal(t, "line", s.entries[1].Line, st.Frames[0].Line)
+		assert.Len(t, "fields", 2, s.entries[2].Fields)
+		_, ok = s.entries[2].Fields[1].Value.(slog.Goroutines)
+		assert.True(t, "goroutines", ok)
+	})
+
+	t.Run("sameOutput", func(t *testing.T) {
+		t.Parallel()
+
+		b := &bytes.Buffer{}
+		l := slog.Make(sloghuman.Sink(b))
+		stdslog.New(slog.Handler(l)).Info("hello", "hi", "we")
+		l.Info(bg, "hello", slog.F("hi", "we"))
+
+		lines := bytes.SplitAfter(b.Bytes(), []byte("\n"))
+		_, rest1, err := entryhuman.StripTimestamp(string(lines[0]))
+		assert.Success(t, "strip timestamp", err)
+		_, rest2, err := entryhuman.StripTimestamp(string(lines[1]))
+		assert.Success(t, "strip timestamp", err)
+		assert.Equal(t, "entry", rest2, rest1)
+	})
+}
+
+func TestHandler_customLevels(t *testing.T) {
+	t.Parallel()
+
+	s := &fakeSink{}
+	sl := stdslog.New(slog.Handler(slog.Make(s).Leveled(levelTrace)))
+	sl.Log(bg, stdslog.LevelDebug-2, "")
+	sl.Log(bg, stdslog.LevelInfo+2, "")
+	sl.Log(bg, stdslog.LevelInfo+1, "")
+	sl.Log(bg, stdslog.LevelDebug-8, "")
+
+	assert.Len(t, "entries", 4, s.entries)
+	assert.Equal(t, "level", levelTrace, s.entries[0].Level)
+	assert.Equal(t, "level", levelNotice, s.entries[1].Level)
+	assert.Equal(t, "level", slog.LevelInfo, s.entries[2].Level)
+	assert.Equal(t, "level", levelTrace, s.entries[3].Level)
+}
@@ -0,0 +1,37 @@
+package slog
+
+import "context"
+
+// Hook processes entries after the Logger has added its fields
+// and names and before the entries are written to the sinks.
+//
+// It can add fields such as the tenant from ctx, rewrite the
+// message or drop the entry by returning false. Hooks must be
+// safe for concurrent use and must not modify the Fields slice
+// of ent in place as it may be shared with other entries.
+type Hook interface {
+	ProcessEntry(ctx context.Context, ent SinkEntry) (SinkEntry, bool)
+}
+
+// HookFunc is a Hook that calls the function.
+//
+//	l = l.AppendHooks(slog.HookFunc(func(ctx context.Context, ent slog.SinkEntry) (slog.SinkEntry, bool) {
+//		ent.Fields = append(ent.Fields[:len(ent.Fields):len(ent.Fields)], slog.F("tenant", tenantFrom(ctx)))
+//		return ent, true
+//	}))
+type HookFunc func(ctx context.Context, ent SinkEntry) (SinkEntry, bool)
+
+// ProcessEntry implements Hook.
+func (fn HookFunc) ProcessEntry(ctx context.Context, ent SinkEntry) (SinkEntry, bool) {
+	return fn(ctx, ent)
+}
+
+// AppendHooks returns a Logger that passes every entry through
+// the hooks in order before writing it to the sinks.
+//
+// The hooks run once per entry regardless of the number of
+// sinks and are inherited by Loggers derived from l.
+func (l Logger) AppendHooks(hooks ...Hook) Logger {
+	l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], hooks...)
+	return l
+}
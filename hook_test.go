@@ -0,0 +1,48 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+type tenantKey struct{}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	s1 := &fakeSink{}
+	s2 := &fakeSink{}
+	calls := 0
+	l := slog.Make(s1, s2).With(slog.F("a", 1)).AppendHooks(
+		slog.HookFunc(func(ctx context.Context, ent slog.SinkEntry) (slog.SinkEntry, bool) {
+			calls++
+			tenant, ok := ctx.Value(tenantKey{}).(string)
+			if ok {
+				ent.Fields = append(ent.Fields[:len(ent.Fields):len(ent.Fields)], slog.F("tenant", tenant))
+			}
+			return ent, true
+		}),
+		slog.HookFunc(func(_ context.Context, ent slog.SinkEntry) (slog.SinkEntry, bool) {
+			ent.Message = "[" + ent.Message + "]"
+			return ent, ent.Message != "[drop]"
+		}),
+	)
+	l = l.Named("derived")
+
+	ctx := context.WithValue(bg, tenantKey{}, "acme")
+	l.Info(ctx, "hello")
+	l.Info(bg, "drop")
+
+	assert.Equal(t, "calls", 2, calls)
+	assert.Len(t, "entries", 1, s1.entries)
+	assert.Len(t, "entries", 1, s2.entries)
+	assert.Equal(t, "msg", "[hello]", s1.entries[0].Message)
+	assert.Equal(t, "names", []string{"derived"}, s1.entries[0].LoggerNames)
+	assert.Equal(t, "fields", slog.M(
+		slog.F("a", 1),
+		slog.F("tenant", "acme"),
+	), s1.entries[0].Fields)
+}
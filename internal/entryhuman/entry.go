@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unicode"
@@ -22,6 +23,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/errchain"
 )
 
 // StripTimestamp strips the timestamp from entry and returns
@@ -67,8 +69,10 @@ func formatValue(v interface{}) (string, error) {
 	if v == nil {
 		return "<nil>", nil
 	}
-	switch v.(type) {
-	case error, fmt.Stringer:
+	switch v := v.(type) {
+	case error:
+		return quote(formatError(v)), nil
+	case fmt.Stringer:
 		return quote(fmt.Sprintf("%+v", v)), nil
 	}
 	typ := reflect.TypeOf(v)
@@ -91,6 +95,56 @@ func formatValue(v interface{}) (string, error) {
 	}
 }
 
+// formatError formats err with %+v unless it wraps other errors
+// with the Unwrap methods of the standard library in which case
+// the chain is written as a tree with the message and type of
+// every error on its own line.
+//
+//	close db (*fmt.wrapError)
+//	  context canceled (*errors.errorString)
+func formatError(err error) string {
+	if _, ok := err.(xerrors.Formatter); ok || !errchain.Wraps(err) {
+		return fmt.Sprintf("%+v", err)
+	}
+	var sb strings.Builder
+	writeErrorTree(&sb, err, "")
+	return sb.String()
+}
+
+func writeErrorTree(sb *strings.Builder, err error, indent string) {
+	for err != nil {
+		if _, ok := err.(xerrors.Formatter); ok {
+			// xerrors print their own chain.
+			sb.WriteString(indent)
+			sb.WriteString(strings.ReplaceAll(fmt.Sprintf("%+v", err), "\n", "\n"+indent))
+			return
+		}
+
+		next, joined := errchain.Unwrap(err)
+		sb.WriteString(indent)
+		if joined != nil {
+			if msg := errchain.JoinMsg(err, joined); msg != "" {
+				sb.WriteString(strings.ReplaceAll(msg, "\n", " "))
+				sb.WriteString(" ")
+			}
+			sb.WriteString("(" + errchain.Type(err) + ")")
+			for _, e := range joined {
+				sb.WriteString("\n")
+				writeErrorTree(sb, e, indent+tab)
+			}
+			return
+		}
+
+		sb.WriteString(errchain.Msg(err, next))
+		sb.WriteString(" (" + errchain.Type(err) + ")")
+		if next != nil {
+			sb.WriteString("\n")
+		}
+		indent += tab
+		err = next
+	}
+}
+
 const tab = "  "
 
 // bracketedLevel is an optimization to avoid extra allocations and calls to strings.ToLower
@@ -110,6 +164,9 @@ func bracketedLevel(l slog.Level) string {
 	case slog.LevelFatal:
 		return "[fata]"
 	default:
+		if c, ok := slog.LookupLevel(l); ok {
+			return "[" + c.Label + "]"
+		}
 		return "[unkn]"
 	}
 }
@@ -236,6 +293,8 @@ func (f Formatter) Fmt(buf *bytes.Buffer, termW io.Writer, ent slog.SinkEntry) {
 	keyStyle := timeStyle
 	equalsStyle := timeStyle
 
+	fields := flattenFields(slog.RedactMap(ent.Fields))
+
 	// Write trace/span directly (do not mutate ent.Fields)
 	if ent.SpanContext.IsValid() {
 		buf.WriteString(tab)
@@ -249,35 +308,43 @@ func (f Formatter) Fmt(buf *bytes.Buffer, termW io.Writer, ent slog.SinkEntry) {
 		buf.WriteString(ent.SpanContext.SpanID().String())
 	}
 
-	// Find a multiline field without mutating ent.Fields.
-	multiIdx := -1
-	for i, fld := range ent.Fields {
-		if multilineVal != "" {
-			break
-		}
+	// Find the multiline fields without mutating ent.Fields.
+	// Only the first multiline string or error is printed as a block
+	// but stack traces are always printed as blocks.
+	var blocks []multilineBlock
+	if multilineVal != "" {
+		blocks = append(blocks, multilineBlock{idx: -1, key: multilineKey, val: multilineVal})
+	}
+	for i, fld := range fields {
 		var s string
 		switch v := fld.Value.(type) {
+		case slog.Stack, slog.Goroutines:
+			blocks = append(blocks, multilineBlock{idx: i, key: fld.Name, val: fmt.Sprint(v)})
+			continue
 		case string:
 			s = v
-		case error, xerrors.Formatter:
+		case error:
+			s = formatError(v)
+		case xerrors.Formatter:
 			s = fmt.Sprintf("%+v", v)
 		}
+		if multilineVal != "" {
+			continue
+		}
 		s = strings.TrimSpace(s)
 		if !strings.Contains(s, "\n") {
 			continue
 		}
-		multiIdx = i
-		multilineKey = fld.Name
 		multilineVal = s
-		break
+		blocks = append(blocks, multilineBlock{idx: i, key: fld.Name, val: s})
 	}
 
-	// Print fields (skip multiline field index).
-	for i, fld := range ent.Fields {
-		if i == multiIdx {
+	// Print fields (skip multiline fields).
+	for i, fld := range fields {
+		if isBlock(blocks, i) {
 			continue
 		}
-		if i < len(ent.Fields) {
+		if i < len(fields) {
 			buf.WriteString(tab)
 		}
 
@@ -299,18 +366,17 @@ func (f Formatter) Fmt(buf *bytes.Buffer, termW io.Writer, ent slog.SinkEntry) {
 		}
 	}
 
-	// Multiline value block
-	if multilineVal != "" {
-		if msg != "..." {
-			buf.WriteString(" ...")
-		}
-
+	// Multiline value blocks
+	if len(blocks) > 0 && msg != "..." {
+		buf.WriteString(" ...")
+	}
+	for _, b := range blocks {
 		buf.WriteString("\n")
-		buf.WriteString(render(termW, keyStyle, multilineKey))
+		buf.WriteString(render(termW, keyStyle, b.key))
 		buf.WriteString("= ")
 
 		// First line up to first newline
-		s := multilineVal
+		s := b.val
 		if n := strings.IndexByte(s, '\n'); n >= 0 {
 			buf.WriteString(s[:n])
 			s = s[n+1:]
@@ -319,7 +385,7 @@ func (f Formatter) Fmt(buf *bytes.Buffer, termW io.Writer, ent slog.SinkEntry) {
 			s = ""
 		}
 
-		indent := strings.Repeat(" ", len(multilineKey)+2)
+		indent := strings.Repeat(" ", len(b.key)+2)
 		for len(s) > 0 {
 			buf.WriteString("\n")
 			// Only indent non-empty lines.
@@ -337,6 +403,57 @@ func (f Formatter) Fmt(buf *bytes.Buffer, termW io.Writer, ent slog.SinkEntry) {
 	}
 }
 
+// multilineBlock is a value printed on its own lines
+// after the rest of the entry.
+type multilineBlock struct {
+	// idx is the index of the field or -1 for the message.
+	idx int
+	key string
+	val string
+}
+
+func isBlock(blocks []multilineBlock, i int) bool {
+	for _, b := range blocks {
+		if b.idx == i {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenFields returns fs with all LogValuer values resolved
+// and the fields of groups prefixed with the group name such
+// as db.query. The fields of LogFielder errors follow the error
+// prefixed with its name such as error.user_id.
+// fs is shared with the other sinks so it is copied
+// instead of modified.
+func flattenFields(fs slog.Map) slog.Map {
+	for _, f := range fs {
+		switch f.Value.(type) {
+		case slog.LogValuer, slog.Map, error:
+			return appendFlattened(make(slog.Map, 0, len(fs)), "", fs)
+		}
+	}
+	return fs
+}
+
+func appendFlattened(dst slog.Map, prefix string, fs slog.Map) slog.Map {
+	for _, f := range fs {
+		v := slog.ResolveValue(f.Value)
+		if m, ok := v.(slog.Map); ok {
+			dst = appendFlattened(dst, prefix+f.Name+".", m)
+			continue
+		}
+		dst = append(dst, slog.F(prefix+f.Name, v))
+		if err, ok := v.(error); ok {
+			if efs := slog.ErrorFields(err); len(efs) > 0 {
+				dst = appendFlattened(dst, prefix+f.Name+".", slog.RedactMap(efs))
+			}
+		}
+	}
+	return dst
+}
+
 var (
 	levelDebugStyle = timeStyle.Copy()
 	levelInfoStyle  = renderer.NewStyle().Foreground(lipgloss.Color("#0091FF"))
@@ -355,11 +472,26 @@ func levelStyle(level slog.Level) lipgloss.Style {
 	case slog.LevelError, slog.LevelFatal, slog.LevelCritical:
 		return levelErrorStyle
 	default:
-		// don't panic
-		return levelErrorStyle
+		c, ok := slog.LookupLevel(level)
+		if !ok {
+			// don't panic
+			return levelErrorStyle
+		}
+		if st, ok := customLevelStyles.Load(c.Color); ok {
+			return st.(lipgloss.Style)
+		}
+		st := renderer.NewStyle()
+		if c.Color != "" {
+			st = st.Foreground(lipgloss.Color(c.Color))
+		}
+		customLevelStyles.Store(c.Color, st)
+		return st
 	}
 }
 
+// customLevelStyles caches the styles of registered levels by color.
+var customLevelStyles sync.Map
+
 var forceColorWriter = io.Writer(&bytes.Buffer{})
 
 // isTTY checks whether the given writer is a *os.File TTY.
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -22,12 +23,24 @@ var kt = time.Date(2000, time.February, 5, 4, 4, 4, 4, time.UTC)
 
 var updateGoldenFiles = flag.Bool("update-golden-files", false, "update golden files in testdata")
 
+const levelNotice = slog.LevelInfo + 5
+
+func init() {
+	slog.RegisterLevel(levelNotice, slog.LevelConfig{Name: "NOTICE", Color: "#00FF00"})
+	slog.RedactNames("api_token")
+}
+
 type testObj struct {
 	foo int
 	bar int
 	dra []byte
 }
 
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" slog:"redact"`
+}
+
 func TestEntry(t *testing.T) {
 	t.Parallel()
 
@@ -145,6 +158,25 @@ func TestEntry(t *testing.T) {
 				),
 			},
 		},
+		{
+			"group",
+			slog.SinkEntry{
+				Level:   slog.LevelInfo,
+				Message: "query",
+				Time:    kt,
+				Fields: slog.M(
+					slog.F("top", 1),
+					slog.Group("db",
+						slog.F("name", "pg"),
+						slog.Group("query",
+							slog.F("sql", "select 1"),
+							slog.F("rows", 3),
+						),
+						slog.Group("empty"),
+					),
+				),
+			},
+		},
 		{
 			"primitiveTypes",
 			slog.SinkEntry{
@@ -204,6 +236,22 @@ func TestEntry(t *testing.T) {
 				),
 			},
 		},
+		{
+			"logValuer",
+			slog.SinkEntry{
+				Level:   slog.LevelInfo,
+				Message: "lazy",
+				Time:    kt,
+				Fields: slog.M(
+					slog.F("count", slog.LogValuerFunc(func() interface{} {
+						return 42
+					})),
+					slog.F("lines", slog.LogValuerFunc(func() interface{} {
+						return "line1\nline2"
+					})),
+				),
+			},
+		},
 		{
 			"allLogLevels",
 			slog.SinkEntry{
@@ -212,6 +260,33 @@ func TestEntry(t *testing.T) {
 				Time:    kt,
 			},
 		},
+		{
+			"customLevel",
+			slog.SinkEntry{
+				Level:   levelNotice,
+				Message: "notice",
+				Time:    kt,
+			},
+		},
+		{
+			"unknownLevel",
+			slog.SinkEntry{
+				Level:   levelNotice + 1,
+				Message: "unknown",
+				Time:    kt,
+			},
+		},
+		{
+			"redacted",
+			slog.SinkEntry{
+				Message: "redacted",
+				Time:    kt,
+				Fields: slog.M(
+					slog.F("token", slog.Redacted("secret")),
+					slog.F("creds", credentials{User: "root", Password: "secret"}),
+				),
+			},
+		},
 		{
 			"fatalLevel",
 			slog.SinkEntry{
@@ -307,7 +382,15 @@ func TestContextErrorFormatting(t *testing.T) {
 		{
 			name:     "WrappedDeadlineExceeded",
 			err:      fmt.Errorf("request failed: %w", context.DeadlineExceeded),
-			expected: "request failed: context deadline exceeded",
+			expected: "error= request failed (*fmt.wrapError)\n         context deadline exceeded (context.deadlineExceededError)",
+		},
+		{
+			name: "Joined",
+			err: errors.Join(
+				fmt.Errorf("close db: %w", context.Canceled),
+				errors.New("flush metrics"),
+			),
+			expected: "error= (*errors.joinError)\n         close db (*fmt.wrapError)\n           context canceled (*errors.errorString)\n         flush metrics (*errors.errorString)",
 		},
 	}
 
@@ -332,6 +415,33 @@ func TestContextErrorFormatting(t *testing.T) {
 	}
 }
 
+type notFoundError struct{}
+
+func (notFoundError) Error() string {
+	return "user not found"
+}
+
+func (notFoundError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("user_id", 42), slog.F("api_token", "secret")}
+}
+
+func TestErrorFields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	entryhuman.Fmt(&buf, io.Discard, slog.SinkEntry{
+		Level:   slog.LevelError,
+		Message: "get user failed",
+		Fields: slog.M(
+			slog.Group("db", slog.Error(fmt.Errorf("get user: %w", notFoundError{}))),
+		),
+	})
+
+	got := buf.String()
+	assert.True(t, "error fields", strings.Contains(got, `db.error.user_id=42`))
+	assert.True(t, "redacted", strings.Contains(got, `db.error.api_token=[REDACTED]`))
+}
+
 func BenchmarkFmt(b *testing.B) {
 	bench := func(b *testing.B, color bool) {
 		nfs := []int{1, 4, 16}
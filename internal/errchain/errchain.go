@@ -0,0 +1,76 @@
+// Package errchain walks the errors wrapped with the Unwrap
+// methods of the standard library such as those created by
+// fmt.Errorf with %w and errors.Join.
+package errchain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Wraps reports whether err wraps other errors.
+func Wraps(err error) bool {
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unwrap returns the error wrapped by err or the errors joined
+// by err if it implements Unwrap() []error.
+func Unwrap(err error) (next error, joined []error) {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		return err.Unwrap(), nil
+	case interface{ Unwrap() []error }:
+		// Drop nil errors to match errors.Is.
+		for _, e := range err.Unwrap() {
+			if e != nil {
+				joined = append(joined, e)
+			}
+		}
+		if joined == nil {
+			joined = []error{}
+		}
+		return nil, joined
+	default:
+		return nil, nil
+	}
+}
+
+// Msg returns the message of err without the message of the
+// error it wraps if err's message ends with ": " followed by
+// it, as it does for fmt.Errorf("context: %w", next).
+func Msg(err, next error) string {
+	msg := err.Error()
+	if next == nil {
+		return msg
+	}
+	if m, ok := strings.CutSuffix(msg, ": "+next.Error()); ok {
+		return m
+	}
+	return msg
+}
+
+// JoinMsg returns the message of err or "" if it is only the
+// messages of the joined errors separated by newlines,
+// as it is for errors.Join.
+func JoinMsg(err error, joined []error) string {
+	msg := err.Error()
+	msgs := make([]string, 0, len(joined))
+	for _, e := range joined {
+		msgs = append(msgs, e.Error())
+	}
+	if msg == strings.Join(msgs, "\n") {
+		return ""
+	}
+	return msg
+}
+
+// Type returns the name of the concrete type of err
+// such as *fmt.wrapError.
+func Type(err error) string {
+	return fmt.Sprintf("%T", err)
+}
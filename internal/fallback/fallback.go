@@ -0,0 +1,21 @@
+// Package fallback holds the sink of the default Logger
+// until one is set with slog.SetDefault.
+//
+// It is set by sloghuman and stored as an interface{}
+// as the root package cannot import sloghuman without
+// an import cycle.
+package fallback
+
+import "sync/atomic"
+
+var sink atomic.Value
+
+// SetSink sets the fallback sink.
+func SetSink(s interface{}) {
+	sink.Store(s)
+}
+
+// Sink returns the fallback sink or nil.
+func Sink() interface{} {
+	return sink.Load()
+}
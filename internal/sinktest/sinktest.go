@@ -0,0 +1,35 @@
+// Package sinktest contains a slog.Sink that records
+// the entries for the tests of the sinks.
+package sinktest
+
+import (
+	"context"
+
+	"cdr.dev/slog/v3"
+)
+
+// Sink records the entries and counts the calls to Sync.
+// It is not safe for concurrent use.
+type Sink struct {
+	Entries []slog.SinkEntry
+	Syncs   int
+}
+
+// LogEntry records e.
+func (s *Sink) LogEntry(_ context.Context, e slog.SinkEntry) {
+	s.Entries = append(s.Entries, e)
+}
+
+// Sync increments Syncs.
+func (s *Sink) Sync() {
+	s.Syncs++
+}
+
+// Messages returns the messages of the recorded entries.
+func (s *Sink) Messages() []string {
+	var msgs []string
+	for _, e := range s.Entries {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
@@ -0,0 +1,196 @@
+package slog
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/xerrors"
+)
+
+// LevelConfig describes how the sinks render a Level.
+type LevelConfig struct {
+	// Name is returned by Level.String such as "NOTICE".
+	Name string
+	// Label is the four letter label used in human readable output
+	// such as "noti". Defaults to the first four letters of Name
+	// in lower case.
+	Label string
+	// Color is the color of Label in human readable output as
+	// a hex string such as "#00FF00". Defaults to no color.
+	Color string
+	// Severity is the name of the Google Cloud Logging severity
+	// such as "NOTICE". Defaults to the severity of the closest
+	// builtin level below the level.
+	Severity string
+}
+
+var builtinLevels = map[Level]LevelConfig{
+	LevelDebug:    {Name: "DEBUG", Label: "debu", Color: "#606366", Severity: "DEBUG"},
+	LevelInfo:     {Name: "INFO", Label: "info", Color: "#0091FF", Severity: "INFO"},
+	LevelWarn:     {Name: "WARN", Label: "warn", Color: "#FFCF0D", Severity: "WARNING"},
+	LevelError:    {Name: "ERROR", Label: "erro", Color: "#FF5A0D", Severity: "ERROR"},
+	LevelCritical: {Name: "CRITICAL", Label: "crit", Color: "#FF5A0D", Severity: "CRITICAL"},
+	LevelFatal:    {Name: "FATAL", Label: "fata", Color: "#FF5A0D", Severity: "CRITICAL"},
+}
+
+var (
+	levelsMu sync.Mutex
+	// levels is copied on write so that it can be read
+	// without locking.
+	levels atomic.Value // map[Level]LevelConfig
+)
+
+func init() {
+	levels.Store(builtinLevels)
+}
+
+// RegisterLevel registers a custom level with the given config
+// so that it is rendered correctly by all sinks and can be parsed.
+//
+//	const LevelTrace = slog.LevelDebug - 5
+//	const LevelNotice = slog.LevelInfo + 5
+//
+//	func init() {
+//		slog.RegisterLevel(LevelTrace, slog.LevelConfig{Name: "TRACE", Color: "#4D4D4D"})
+//		slog.RegisterLevel(LevelNotice, slog.LevelConfig{Name: "NOTICE", Severity: "NOTICE"})
+//	}
+//
+// Levels should be registered during initialization.
+// It panics if Name is empty or the level or its name
+// is already registered.
+func RegisterLevel(level Level, c LevelConfig) {
+	if c.Name == "" {
+		panic("slog: level name must not be empty")
+	}
+	if c.Label == "" {
+		c.Label = strings.ToLower(c.Name)
+		if len(c.Label) > 4 {
+			c.Label = c.Label[:4]
+		}
+	}
+	c.Label = fmt.Sprintf("%-4s", c.Label)
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	m := levels.Load().(map[Level]LevelConfig)
+	if old, ok := m[level]; ok {
+		panic(fmt.Sprintf("slog: level %v already registered as %v", int(level), old.Name))
+	}
+	for l, lc := range m {
+		if strings.EqualFold(lc.Name, c.Name) {
+			panic(fmt.Sprintf("slog: level name %v already registered for level %v", c.Name, int(l)))
+		}
+	}
+
+	m2 := make(map[Level]LevelConfig, len(m)+1)
+	for l, lc := range m {
+		m2[l] = lc
+	}
+	m2[level] = c
+	levels.Store(m2)
+}
+
+// LookupLevel returns the config of a builtin or registered level.
+func LookupLevel(level Level) (LevelConfig, bool) {
+	c, ok := levels.Load().(map[Level]LevelConfig)[level]
+	return c, ok
+}
+
+// floorLevel returns the highest known level at or below level.
+// If there is none, the lowest known level is returned.
+func floorLevel(level Level) Level {
+	m := levels.Load().(map[Level]LevelConfig)
+	if _, ok := m[level]; ok {
+		return level
+	}
+
+	known := make([]Level, 0, len(m))
+	for l := range m {
+		known = append(known, l)
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i] < known[j]
+	})
+	i := sort.Search(len(known), func(i int) bool {
+		return known[i] > level
+	})
+	if i == 0 {
+		return known[0]
+	}
+	return known[i-1]
+}
+
+// ParseLevel parses the name of a builtin or registered level
+// case insensitively. It also accepts the "slog.Level(n)" form
+// returned by Level.String for unknown levels.
+func ParseLevel(s string) (Level, error) {
+	s = strings.TrimSpace(s)
+	for l, c := range levels.Load().(map[Level]LevelConfig) {
+		if strings.EqualFold(s, c.Name) {
+			return l, nil
+		}
+	}
+	if n, ok := strings.CutPrefix(s, "slog.Level("); ok {
+		if n, ok := strings.CutSuffix(n, ")"); ok {
+			i, err := strconv.Atoi(n)
+			if err == nil {
+				return Level(i), nil
+			}
+		}
+	}
+	return 0, xerrors.Errorf("unknown level %q", s)
+}
+
+var (
+	_ encoding.TextMarshaler   = Level(0)
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+	_ flag.Value               = (*Level)(nil)
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+// using ParseLevel.
+func (l *Level) UnmarshalText(b []byte) error {
+	level, err := ParseLevel(string(b))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Set implements flag.Value using ParseLevel.
+//
+//	level := slog.LevelInfo
+//	flag.Var(&level, "log-level", "minimum level to log")
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// LevelFromEnv parses the level in the environment variable key
+// such as SLOG_LEVEL with ParseLevel.
+//
+// It returns fallback if the variable is unset or empty.
+func LevelFromEnv(key string, fallback Level) (Level, error) {
+	s := os.Getenv(key)
+	if s == "" {
+		return fallback, nil
+	}
+	level, err := ParseLevel(s)
+	if err != nil {
+		return fallback, xerrors.Errorf("failed to parse %v: %w", key, err)
+	}
+	return level, nil
+}
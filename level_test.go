@@ -0,0 +1,149 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+const (
+	levelTrace  = slog.LevelDebug - 5
+	levelNotice = slog.LevelInfo + 5
+)
+
+func init() {
+	slog.RegisterLevel(levelTrace, slog.LevelConfig{Name: "TRACE"})
+	slog.RegisterLevel(levelNotice, slog.LevelConfig{Name: "NOTICE", Label: "note", Severity: "NOTICE"})
+}
+
+func TestRegisterLevel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "string", "TRACE", levelTrace.String())
+	assert.Equal(t, "string", "NOTICE", levelNotice.String())
+
+	c, ok := slog.LookupLevel(levelTrace)
+	assert.True(t, "registered", ok)
+	assert.Equal(t, "config", slog.LevelConfig{Name: "TRACE", Label: "trac"}, c)
+
+	c, ok = slog.LookupLevel(slog.LevelWarn)
+	assert.True(t, "builtin", ok)
+	assert.Equal(t, "name", "WARN", c.Name)
+
+	_, ok = slog.LookupLevel(slog.LevelWarn + 1)
+	assert.False(t, "unregistered", ok)
+
+	rules, err := slog.ParseLevelRules("db=notice")
+	assert.Success(t, "parse rules", err)
+	assert.Equal(t, "level", levelNotice, rules[0].Level)
+
+	s := &fakeSink{}
+	l := slog.Make(s).Leveled(levelTrace)
+	l.Log(bg, slog.SinkEntry{Level: levelTrace})
+	l.Leveled(levelNotice).Info(bg, "dropped")
+	assert.Len(t, "entries", 1, s.entries)
+
+	panics := func(fn func()) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		fn()
+		return false
+	}
+	assert.True(t, "duplicate level", panics(func() {
+		slog.RegisterLevel(slog.LevelInfo, slog.LevelConfig{Name: "OTHER"})
+	}))
+	assert.True(t, "duplicate name", panics(func() {
+		slog.RegisterLevel(slog.LevelInfo+1, slog.LevelConfig{Name: "notice"})
+	}))
+	assert.True(t, "empty name", panics(func() {
+		slog.RegisterLevel(slog.LevelInfo+1, slog.LevelConfig{})
+	}))
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range []slog.Level{
+		slog.LevelDebug,
+		slog.LevelInfo,
+		slog.LevelWarn,
+		slog.LevelError,
+		slog.LevelCritical,
+		slog.LevelFatal,
+		levelTrace,
+		levelNotice,
+		slog.Level(12),
+		slog.Level(-3),
+	} {
+		pl, err := slog.ParseLevel(l.String())
+		assert.Success(t, "parse level", err)
+		assert.Equal(t, "level", l, pl)
+	}
+
+	l, err := slog.ParseLevel(" warn ")
+	assert.Success(t, "parse level", err)
+	assert.Equal(t, "level", slog.LevelWarn, l)
+
+	_, err = slog.ParseLevel("loud")
+	assert.Error(t, "unknown level", err)
+	_, err = slog.ParseLevel("slog.Level(x)")
+	assert.Error(t, "bad number", err)
+}
+
+func TestLevel_Text(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Level slog.Level `json:"level"`
+	}
+	err := json.Unmarshal([]byte(`{"level":"debug"}`), &cfg)
+	assert.Success(t, "unmarshal", err)
+	assert.Equal(t, "level", slog.LevelDebug, cfg.Level)
+
+	cfg.Level = levelNotice
+	b, err := json.Marshal(cfg)
+	assert.Success(t, "marshal", err)
+	assert.Equal(t, "json", `{"level":"NOTICE"}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"level":"loud"}`), &cfg)
+	assert.Error(t, "unmarshal", err)
+}
+
+func TestLevel_Flag(t *testing.T) {
+	t.Parallel()
+
+	level := slog.LevelInfo
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&level, "level", "")
+
+	err := fs.Parse([]string{"-level", "ERROR"})
+	assert.Success(t, "parse flags", err)
+	assert.Equal(t, "level", slog.LevelError, level)
+
+	err = fs.Parse([]string{"-level", "loud"})
+	assert.Error(t, "parse flags", err)
+}
+
+func TestLevelFromEnv(t *testing.T) {
+	// This can't be parallel since it sets environment variables.
+
+	l, err := slog.LevelFromEnv("SLOG_TEST_LEVEL", slog.LevelWarn)
+	assert.Success(t, "unset", err)
+	assert.Equal(t, "level", slog.LevelWarn, l)
+
+	t.Setenv("SLOG_TEST_LEVEL", "Debug")
+	l, err = slog.LevelFromEnv("SLOG_TEST_LEVEL", slog.LevelWarn)
+	assert.Success(t, "set", err)
+	assert.Equal(t, "level", slog.LevelDebug, l)
+
+	t.Setenv("SLOG_TEST_LEVEL", "loud")
+	l, err = slog.LevelFromEnv("SLOG_TEST_LEVEL", slog.LevelWarn)
+	assert.Error(t, "invalid", err)
+	assert.Equal(t, "level", slog.LevelWarn, l)
+}
@@ -0,0 +1,54 @@
+package slog
+
+import "context"
+
+// LeveledSink returns a Sink that only writes entries
+// equal to or above level to s.
+//
+// It allows a single Logger to write debug entries to a
+// local file, info entries to stdout and only errors to
+// a remote service:
+//
+//	l := slog.Make(
+//		slog.LeveledSink(slogjson.Sink(f), slog.LevelDebug),
+//		slog.LeveledSink(sloghuman.Sink(os.Stdout), slog.LevelInfo),
+//		slog.LeveledSink(slogstackdriver.Sink(w), slog.LevelError),
+//	).Leveled(slog.LevelDebug)
+//
+// The Logger's own level still applies so it must be at or
+// below the lowest sink level. The Logger only builds entries
+// that at least one of its sinks logs.
+//
+// The level of the sink is not overridden by WithLevel.
+func LeveledSink(s Sink, level Level) Sink {
+	return leveledSink{
+		s:     s,
+		level: level,
+	}
+}
+
+type leveledSink struct {
+	s     Sink
+	level Level
+}
+
+var _ LevelEnabler = leveledSink{}
+
+func (s leveledSink) Enabled(ctx context.Context, level Level) bool {
+	if level < s.level {
+		return false
+	}
+	le, ok := s.s.(LevelEnabler)
+	return !ok || le.Enabled(ctx, level)
+}
+
+func (s leveledSink) LogEntry(ctx context.Context, ent SinkEntry) {
+	if ent.Level < s.level {
+		return
+	}
+	s.s.LogEntry(ctx, ent)
+}
+
+func (s leveledSink) Sync() {
+	s.s.Sync()
+}
@@ -0,0 +1,150 @@
+package slog
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// LevelRule overrides the level of the Loggers whose
+// names match Pattern.
+//
+// Pattern is matched against the logger names joined with ".".
+// A pattern matches its names and every name below them so
+// "coderd.provisioner" matches "coderd.provisioner" and
+// "coderd.provisioner.runner" but not "coderd".
+// A "*" segment matches any single name and the pattern "*"
+// on its own matches every Logger, including unnamed ones.
+type LevelRule struct {
+	Pattern string
+	Level   Level
+}
+
+// ParseLevelRules parses a comma separated list of
+// pattern=level pairs such as
+//
+//	coderd.provisioner=debug,http.client=warn,*=info
+//
+// Levels are parsed with ParseLevel.
+func ParseLevelRules(s string) ([]LevelRule, error) {
+	var rules []LevelRule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pattern, lvl, ok := strings.Cut(part, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, xerrors.Errorf("invalid level rule %q: expected pattern=level", part)
+		}
+		level, err := ParseLevel(lvl)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid level rule %q: %w", part, err)
+		}
+		rules = append(rules, LevelRule{
+			Pattern: pattern,
+			Level:   level,
+		})
+	}
+	return rules, nil
+}
+
+// LeveledRules returns a Logger that uses the level of the
+// most specific rule matching the entry's logger names instead
+// of the level set with Leveled or LeveledVar.
+//
+// Longer patterns are more specific and at equal lengths,
+// patterns with fewer "*" segments are more specific.
+// When no rule matches, the Logger's level is used.
+//
+// It replaces any rules already set on the Logger.
+func (l Logger) LeveledRules(rules ...LevelRule) Logger {
+	l.rules = makeLevelRules(rules)
+	l.sinks = append([]Sink(nil), l.sinks...)
+	return l
+}
+
+// levelFor returns the minimum level of entries logged
+// with the given names in addition to the Logger's names.
+func (l Logger) levelFor(names []string) Level {
+	if len(l.rules) > 0 {
+		level, ok := l.rules.match(l.names, names)
+		if ok {
+			return level
+		}
+	}
+	return l.minLevel()
+}
+
+type levelRule struct {
+	segs      []string
+	wildcards int
+	level     Level
+}
+
+// levelRules is sorted from the most to the least specific rule.
+type levelRules []levelRule
+
+func makeLevelRules(rules []LevelRule) levelRules {
+	if len(rules) == 0 {
+		return nil
+	}
+	rs := make(levelRules, 0, len(rules))
+	for _, r := range rules {
+		lr := levelRule{
+			segs:  strings.Split(r.Pattern, "."),
+			level: r.Level,
+		}
+		for _, seg := range lr.segs {
+			if seg == "*" {
+				lr.wildcards++
+			}
+		}
+		rs = append(rs, lr)
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		if len(rs[i].segs) != len(rs[j].segs) {
+			return len(rs[i].segs) > len(rs[j].segs)
+		}
+		return rs[i].wildcards < rs[j].wildcards
+	})
+	return rs
+}
+
+func (rs levelRules) match(names, names2 []string) (Level, bool) {
+	for _, r := range rs {
+		if r.matches(names, names2) {
+			return r.level, true
+		}
+	}
+	return 0, false
+}
+
+func (r levelRule) matches(names, names2 []string) bool {
+	if len(r.segs) == 1 && r.segs[0] == "*" {
+		return true
+	}
+
+	i := 0
+	for _, ns := range [2][]string{names, names2} {
+		for _, name := range ns {
+			for {
+				if i == len(r.segs) {
+					return true
+				}
+				seg, rest, more := strings.Cut(name, ".")
+				if r.segs[i] != "*" && r.segs[i] != seg {
+					return false
+				}
+				i++
+				if !more {
+					break
+				}
+				name = rest
+			}
+		}
+	}
+	return i == len(r.segs)
+}
@@ -0,0 +1,62 @@
+package slog_test
+
+import (
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func TestParseLevelRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := slog.ParseLevelRules("coderd.provisioner=debug, http.client=WARN,*=info,")
+	assert.Success(t, "parse rules", err)
+	assert.Equal(t, "rules", []slog.LevelRule{
+		{Pattern: "coderd.provisioner", Level: slog.LevelDebug},
+		{Pattern: "http.client", Level: slog.LevelWarn},
+		{Pattern: "*", Level: slog.LevelInfo},
+	}, rules)
+
+	_, err = slog.ParseLevelRules("coderd")
+	assert.Error(t, "missing level", err)
+	_, err = slog.ParseLevelRules("coderd=loud")
+	assert.Error(t, "unknown level", err)
+}
+
+func TestLogger_LeveledRules(t *testing.T) {
+	t.Parallel()
+
+	s := &fakeSink{}
+	l := slog.Make(s).Leveled(slog.LevelError).LeveledRules(
+		slog.LevelRule{Pattern: "*", Level: slog.LevelInfo},
+		slog.LevelRule{Pattern: "coderd.provisioner", Level: slog.LevelDebug},
+		slog.LevelRule{Pattern: "coderd.*.db", Level: slog.LevelWarn},
+		slog.LevelRule{Pattern: "http.client", Level: slog.LevelWarn},
+	)
+
+	l.Debug(bg, "root debug")
+	l.Info(bg, "root info")
+	l.Named("coderd").Named("provisioner").Debug(bg, "provisioner debug")
+	l.Named("coderd.provisioner").Named("runner").Debug(bg, "runner debug")
+	l.Named("coderd").Debug(bg, "coderd debug")
+	l.Named("coderd.provisioner.db").Info(bg, "db info")
+	l.Named("coderd.api.db").Info(bg, "db info")
+	l.Named("http").Named("client").Info(bg, "client info")
+	l.Named("http").Log(bg, slog.SinkEntry{
+		Level:       slog.LevelWarn,
+		Message:     "client warn",
+		LoggerNames: []string{"client"},
+	})
+
+	var msgs []string
+	for _, e := range s.entries {
+		msgs = append(msgs, e.Message)
+	}
+	assert.Equal(t, "messages", []string{
+		"root info",
+		"provisioner debug",
+		"runner debug",
+		"client warn",
+	}, msgs)
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3/internal/errchain"
 )
 
 // Map represents an ordered map of fields.
@@ -20,23 +22,35 @@ var _ json.Marshaler = Map(nil)
 //
 // It is guaranteed to return a nil error.
 // Any error marshalling a field will become the field's value.
+// Duplicate names are suffixed like DuplicateFieldsSuffix
+// so that the object never has duplicate keys.
 //
 // Every field value is encoded with the following process:
 //
-// 1. json.Marshaller is handled.
+// 1. LogValuer is resolved with ResolveValue.
+//
+// 2. Redacted values are replaced as described by RedactMap.
 //
-// 2. xerrors.Formatter is handled.
+// 3. json.Marshaller is handled.
 //
-// 3. structs that have a field with a json tag are encoded with json.Marshal.
+// 4. xerrors.Formatter, LogFielder and errors wrapping other errors
+// with Unwrap() error or Unwrap() []error are encoded as a chain of
+// objects with the message and type of every error. Joined errors
+// have the chains of the errors they join in an errors array and
+// the fields of LogFielder errors are in a fields object.
 //
-// 4. error and fmt.Stringer is handled.
+// 5. structs that have a field with a json tag are encoded with json.Marshal.
 //
-// 5. slices and arrays go through the encode function for every element.
+// 6. error and fmt.Stringer is handled.
 //
-// 6. For values that cannot be encoded with json.Marshal, fmt.Sprintf("%+v") is used.
+// 7. slices and arrays go through the encode function for every element.
 //
-// 7. json.Marshal(v) is used for all other values.
+// 8. For values that cannot be encoded with json.Marshal, fmt.Sprintf("%+v") is used.
+//
+// 9. json.Marshal(v) is used for all other values.
 func (m Map) MarshalJSON() ([]byte, error) {
+	m = suffixDuplicates(RedactMap(m))
+
 	b := &bytes.Buffer{}
 	b.WriteByte('{')
 	for i, f := range m {
@@ -71,6 +85,9 @@ func marshalList(rv reflect.Value) []byte {
 }
 
 func encode(v interface{}) []byte {
+	v = ResolveValue(v)
+	v, _ = redact.Load().redactValue(v, 0)
+
 	if vr, ok := v.(driver.Valuer); ok {
 		var err error
 		v, err = vr.Value()
@@ -84,6 +101,10 @@ func encode(v interface{}) []byte {
 		return encodeJSON(v)
 	case xerrors.Formatter:
 		return encode(errorChain(v))
+	case error:
+		if _, ok := v.(LogFielder); ok || errchain.Wraps(v) {
+			return encode(errorChain(v))
+		}
 	}
 
 	rv := reflect.Indirect(reflect.ValueOf(v))
@@ -145,28 +166,60 @@ func encodeJSON(v interface{}) []byte {
 	return b
 }
 
-func errorChain(f xerrors.Formatter) []interface{} {
+func errorChain(err error) []interface{} {
 	var errs []interface{}
-
-	next := error(f)
-	for {
-		f, ok := next.(xerrors.Formatter)
-		if !ok {
-			errs = append(errs, next)
-			return errs
+	for err != nil {
+		fields := logFields(err)
+		if f, ok := err.(xerrors.Formatter); ok {
+			p := &xerrorPrinter{}
+			err = f.FormatError(p)
+			p.e.Fields = fields
+			errs = append(errs, p.e)
+			continue
 		}
 
-		p := &xerrorPrinter{}
-		next = f.FormatError(p)
-		errs = append(errs, p.e)
+		next, joined := errchain.Unwrap(err)
+		if joined != nil {
+			je := joinError{
+				Msg:    errchain.JoinMsg(err, joined),
+				Type:   errchain.Type(err),
+				Fields: fields,
+				Errors: make([][]interface{}, 0, len(joined)),
+			}
+			for _, e := range joined {
+				je.Errors = append(je.Errors, errorChain(e))
+			}
+			return append(errs, je)
+		}
+		errs = append(errs, stdError{
+			Msg:    errchain.Msg(err, next),
+			Type:   errchain.Type(err),
+			Fields: fields,
+		})
+		err = next
 	}
+	return errs
 }
 
 type wrapError struct {
 	Msg string `json:"msg"`
 	Fun string `json:"fun"`
 	// file:line
-	Loc string `json:"loc"`
+	Loc    string `json:"loc"`
+	Fields Map    `json:"fields,omitempty"`
+}
+
+type stdError struct {
+	Msg    string `json:"msg"`
+	Type   string `json:"type"`
+	Fields Map    `json:"fields,omitempty"`
+}
+
+type joinError struct {
+	Msg    string          `json:"msg,omitempty"`
+	Type   string          `json:"type"`
+	Fields Map             `json:"fields,omitempty"`
+	Errors [][]interface{} `json:"errors"`
 }
 
 type xerrorPrinter struct {
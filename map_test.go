@@ -70,7 +70,10 @@ func TestMap(t *testing.T) {
 					"fun": "cdr.dev/slog/v3_test.TestMap.func2",
 					"loc": "`+mapTestFile+`:43"
 				},
-				"EOF"
+				{
+					"msg": "EOF",
+					"type": "*errors.errorString"
+				}
 			],
 			"meow": {
 				"izi": "sogood",
@@ -94,9 +97,16 @@ func TestMap(t *testing.T) {
 					{
 						"msg": "failed to marshal to JSON",
 						"fun": "cdr.dev/slog/v3.encodeJSON",
-						"loc": "`+mapTestFile+`:140"
+						"loc": "`+mapTestFile+`:161"
+					},
+					{
+						"msg": "json: error calling MarshalJSON for type slog_test.complexJSON",
+						"type": "*json.MarshalerError"
 					},
-					"json: error calling MarshalJSON for type slog_test.complexJSON: json: unsupported type: complex128"
+					{
+						"msg": "json: unsupported type: complex128",
+						"type": "*json.UnsupportedTypeError"
+					}
 				],
 				"type": "slog_test.complexJSON",
 				"value": "(10+10i)"
@@ -232,6 +242,27 @@ func TestMap(t *testing.T) {
 		}`)
 	})
 
+	t.Run("logValuer", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, slog.M(
+			slog.F("val", slog.LogValuerFunc(func() interface{} {
+				return slog.M(
+					slog.F("nested", slog.LogValuerFunc(func() interface{} {
+						return []int{1, 2}
+					})),
+				)
+			})),
+		), `{
+			"val": {
+				"nested": [
+					1,
+					2
+				]
+			}
+		}`)
+	})
+
 	t.Run("contextCanceled", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,145 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecoverOptions configures Recover.
+type RecoverOptions struct {
+	// Message is the message of the logged entry.
+	// It defaults to "panic".
+	Message string
+
+	// RePanic makes Recover panic again with the recovered
+	// value once it has been logged.
+	RePanic bool
+
+	// Err is set to a *PanicError describing the panic so
+	// that the function deferring Recover can return it
+	// with a named result.
+	Err *error
+}
+
+// PanicError is the error set by Recover when
+// RecoverOptions.Err is set.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack of the panicking goroutine
+	// starting at the panic site.
+	Stack Stack
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover recovers from a panic and logs the panic value,
+// its type and the stack at LevelCritical with the location
+// of the panic site. It then calls Sync on the sinks of l.
+//
+// It must be deferred directly:
+//
+//	func handle(ctx context.Context) (err error) {
+//		defer slog.Recover(ctx, log, &slog.RecoverOptions{Err: &err})
+//		...
+//	}
+//
+// opts may be nil in which case the panic is logged and
+// swallowed.
+func Recover(ctx context.Context, l Logger, opts *RecoverOptions) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if opts == nil {
+		opts = &RecoverOptions{}
+	}
+
+	// Skip Recover itself.
+	st := panicStack(1)
+	l.logPanic(ctx, r, st, opts.Message)
+	l.Sync()
+
+	if opts.Err != nil {
+		*opts.Err = &PanicError{
+			Value: r,
+			Stack: st,
+		}
+	}
+	if opts.RePanic {
+		panic(r)
+	}
+}
+
+// Go runs fn in a new goroutine and logs any panic
+// in it with Recover instead of crashing the process.
+func Go(ctx context.Context, l Logger, fn func()) {
+	go func() {
+		defer Recover(ctx, l, nil)
+		fn()
+	}()
+}
+
+func (l Logger) logPanic(ctx context.Context, r interface{}, st Stack, msg string) {
+	if !l.Enabled(ctx, LevelCritical) {
+		return
+	}
+	if msg == "" {
+		msg = "panic"
+	}
+
+	fields := Map{
+		F("panic", r),
+		F("panic_type", fmt.Sprintf("%T", r)),
+		F("stack", st),
+	}
+	ent := SinkEntry{
+		Time:        time.Now().UTC(),
+		Level:       LevelCritical,
+		Message:     msg,
+		Fields:      fieldsFromContext(ctx).append(l.groups.nest(fields)),
+		SpanContext: trace.SpanContextFromContext(ctx),
+	}
+	if len(st.Frames) > 0 {
+		f := st.Frames[0]
+		ent.Func = f.Func
+		ent.File = f.File
+		ent.Line = f.Line
+	}
+	l.logEntry(ctx, ent)
+}
+
+// panicStack returns the stack of the panicking goroutine
+// starting at the panic site. It must be called by the
+// deferred function that recovered the panic.
+func panicStack(skip int) Stack {
+	s := captureStack(skip + 1)
+	for i, f := range s.Frames {
+		if f.Func != "runtime.gopanic" {
+			continue
+		}
+		// Runtime errors such as nil pointer dereferences
+		// panic from runtime.sigpanic or runtime.panicIndex,
+		// skip those as well.
+		frames := s.Frames[i+1:]
+		for len(frames) > 1 && strings.HasPrefix(frames[0].Func, "runtime.") {
+			frames = frames[1:]
+		}
+		s.Frames = frames
+		break
+	}
+	return s
+}
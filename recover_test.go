@@ -0,0 +1,134 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("swallow", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		func() {
+			defer slog.Recover(bg, slog.Make(s).Named("worker"), nil)
+			panic("boom")
+		}()
+
+		assert.Len(t, "entries", 1, s.entries)
+		ent := s.entries[0]
+		assert.Equal(t, "level", slog.LevelCritical, ent.Level)
+		assert.Equal(t, "msg", "panic", ent.Message)
+		assert.Equal(t, "names", []string{"worker"}, ent.LoggerNames)
+		assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestRecover.func1.1", ent.Func)
+		assert.Equal(t, "file", slogTestFile[:len(slogTestFile)-len("slog_test.go")]+"recover_test.go", ent.File)
+		assert.Equal(t, "line", 22, ent.Line)
+		assert.Equal(t, "syncs", 1, s.syncs)
+
+		assert.Len(t, "fields", 3, ent.Fields)
+		assert.Equal(t, "panic", slog.F("panic", "boom"), ent.Fields[0])
+		assert.Equal(t, "panic_type", slog.F("panic_type", "string"), ent.Fields[1])
+		st, ok := ent.Fields[2].Value.(slog.Stack)
+		assert.True(t, "stack", ok)
+		assert.Equal(t, "func", ent.Func, st.Frames[0].Func)
+	})
+
+	t.Run("runtimeError", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		func() {
+			defer slog.Recover(bg, slog.Make(s), nil)
+			var p *fakeSink
+			p.syncs++
+		}()
+
+		assert.Len(t, "entries", 1, s.entries)
+		assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestRecover.func2.1", s.entries[0].Func)
+		assert.Equal(t, "line", 50, s.entries[0].Line)
+		_, ok := s.entries[0].Fields[0].Value.(runtime.Error)
+		assert.True(t, "runtime error", ok)
+	})
+
+	t.Run("err", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		errBoom := errors.New("boom")
+		err := func() (err error) {
+			defer slog.Recover(bg, slog.Make(s), &slog.RecoverOptions{
+				Message: "handler panicked",
+				Err:     &err,
+			})
+			panic(errBoom)
+		}()
+
+		assert.Len(t, "entries", 1, s.entries)
+		assert.Equal(t, "msg", "handler panicked", s.entries[0].Message)
+		assert.Error(t, "err", err)
+		assert.Equal(t, "err", "panic: boom", err.Error())
+		assert.True(t, "is", errors.Is(err, errBoom))
+
+		var perr *slog.PanicError
+		assert.True(t, "as", errors.As(err, &perr))
+		assert.Equal(t, "line", s.entries[0].Line, perr.Stack.Frames[0].Line)
+	})
+
+	t.Run("rePanic", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		var r interface{}
+		func() {
+			defer func() {
+				r = recover()
+			}()
+			defer slog.Recover(bg, slog.Make(s), &slog.RecoverOptions{RePanic: true})
+			panic("boom")
+		}()
+
+		assert.Len(t, "entries", 1, s.entries)
+		assert.Equal(t, "recovered", "boom", r)
+	})
+
+	t.Run("noPanic", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		func() {
+			defer slog.Recover(bg, slog.Make(s), nil)
+		}()
+
+		assert.Len(t, "entries", 0, s.entries)
+		assert.Equal(t, "syncs", 0, s.syncs)
+	})
+}
+
+type chanSink chan slog.SinkEntry
+
+func (s chanSink) LogEntry(_ context.Context, e slog.SinkEntry) {
+	s <- e
+}
+
+func (s chanSink) Sync() {}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	s := make(chanSink, 1)
+	slog.Go(bg, slog.Make(s), func() {
+		panic("boom")
+	})
+
+	ent := <-s
+	assert.Equal(t, "level", slog.LevelCritical, ent.Level)
+	assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestGo.func1", ent.Func)
+	assert.Equal(t, "line", 127, ent.Line)
+}
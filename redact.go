@@ -0,0 +1,449 @@
+package slog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RedactedText replaces the redacted values in the output of all sinks.
+const RedactedText = "[REDACTED]"
+
+// Redacted wraps a value that must never be written by a sink
+// such as a password or token. It is written as RedactedText.
+//
+//	l.Info(ctx, "logged in", slog.F("token", slog.Redacted(token)))
+//
+// To redact struct fields, tag them with `slog:"redact"`:
+//
+//	type Credentials struct {
+//		User     string `json:"user"`
+//		Password string `json:"password" slog:"redact"`
+//	}
+//
+// See RedactNames to redact values by their name instead.
+func Redacted(v interface{}) LogValuer {
+	// v is dropped so that it cannot leak through
+	// a sink that does not resolve LogValuers.
+	return redactedValue{}
+}
+
+type redactedValue struct{}
+
+func (redactedValue) LogValue() interface{} {
+	return RedactedText
+}
+
+func (redactedValue) String() string {
+	return RedactedText
+}
+
+func (redactedValue) GoString() string {
+	return RedactedText
+}
+
+func (redactedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedText)
+}
+
+type redactRules struct {
+	patterns []string
+	// types caches whether values of a reflect.Type
+	// may contain redacted values.
+	types sync.Map // map[reflect.Type]bool
+}
+
+var (
+	redactMu sync.Mutex
+	redact   atomic.Pointer[redactRules]
+)
+
+func init() {
+	redact.Store(&redactRules{})
+}
+
+// RedactNames registers names whose values are redacted
+// by all sinks in addition to the values wrapped with Redacted
+// and the struct fields tagged with `slog:"redact"`.
+//
+// The names are matched case insensitively against field
+// names, the keys of maps with string keys and the JSON names
+// of struct fields. They may contain the wildcards of path.Match
+// such as "*_token".
+//
+// Names should be registered during initialization.
+// It panics if a name is not a valid pattern.
+func RedactNames(names ...string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	rules := &redactRules{
+		patterns: append([]string(nil), redact.Load().patterns...),
+	}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if _, err := path.Match(name, ""); err != nil {
+			panic("slog: invalid redacted name " + name + ": " + err.Error())
+		}
+		rules.patterns = append(rules.patterns, name)
+	}
+	redact.Store(rules)
+}
+
+// RedactMap returns m with the values of redacted names and
+// the values containing Redacted values or redacted struct
+// fields replaced with RedactedText. The values of the fields
+// that are changed are resolved with ResolveValue.
+//
+// It returns m itself if nothing is redacted.
+// Map.MarshalJSON calls it so only sinks that encode values
+// differently need to call it.
+func RedactMap(m Map) Map {
+	return redact.Load().redactMap(m, 0)
+}
+
+func (r *redactRules) redactMap(m Map, depth int) Map {
+	var m2 Map
+	for i, f := range m {
+		v, changed := r.redactField(f.Name, f.Value, depth)
+		if m2 == nil {
+			if !changed {
+				continue
+			}
+			m2 = make(Map, i, len(m))
+			copy(m2, m[:i])
+		}
+		m2 = append(m2, F(f.Name, v))
+	}
+	if m2 == nil {
+		return m
+	}
+	return m2
+}
+
+// sameValue reports whether v2 is the unmodified v.
+func sameValue(v, v2 interface{}) bool {
+	if m, ok := v.(Map); ok {
+		m2, ok := v2.(Map)
+		return ok && len(m) == len(m2) && (len(m) == 0 || &m[0] == &m2[0])
+	}
+	t := reflect.TypeOf(v)
+	if t != reflect.TypeOf(v2) {
+		return false
+	}
+	return t == nil || !t.Comparable() || v == v2
+}
+
+// maxRedactDepth bounds the recursion into values so that
+// cyclic values cannot overflow the stack. Deeper values
+// are redacted entirely.
+const maxRedactDepth = 64
+
+func (r *redactRules) redactField(name string, v interface{}, depth int) (interface{}, bool) {
+	if r.matches(name) {
+		return RedactedText, true
+	}
+	return r.redactValue(v, depth)
+}
+
+// redactValue returns v or, if it contains redacted values,
+// a copy of it with them replaced by RedactedText and true.
+func (r *redactRules) redactValue(v interface{}, depth int) (interface{}, bool) {
+	if depth > maxRedactDepth {
+		return RedactedText, true
+	}
+	switch v := v.(type) {
+	case Map:
+		m := r.redactMap(v, depth+1)
+		return m, !sameValue(v, m)
+	case LogValuer:
+		v2, _ := r.redactValue(ResolveValue(v), depth+1)
+		return v2, true
+	}
+
+	t := reflect.TypeOf(v)
+	if t == nil || !r.mayRedact(t) {
+		return v, false
+	}
+	return r.redactReflect(reflect.ValueOf(v), depth+1)
+}
+
+func (r *redactRules) matches(name string) bool {
+	if len(r.patterns) == 0 {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, p := range r.patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	logValuerType     = reflect.TypeOf((*LogValuer)(nil)).Elem()
+)
+
+// mayRedact reports whether values of t may contain redacted values.
+//
+// Types implementing json.Marshaler encode themselves and are
+// never redacted. Interfaces may hold any value so they always
+// may contain redacted values and their dynamic value is checked
+// by redactReflect.
+func (r *redactRules) mayRedact(t reflect.Type) bool {
+	if may, ok := r.types.Load(t); ok {
+		return may.(bool)
+	}
+	may := r.computeMayRedact(t, map[reflect.Type]struct{}{})
+	r.types.Store(t, may)
+	return may
+}
+
+// computeMayRedact computes mayRedact for t. visiting holds the
+// types being computed to terminate recursive types.
+func (r *redactRules) computeMayRedact(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if may, ok := r.types.Load(t); ok {
+		return may.(bool)
+	}
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	if t.Kind() != reflect.Interface && (t.Implements(jsonMarshalerType) || t.Implements(logValuerType)) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return r.computeMayRedact(t.Elem(), visiting)
+	case reflect.Map:
+		return (t.Key().Kind() == reflect.String && len(r.patterns) > 0) || r.computeMayRedact(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			ft := t.Field(i)
+			if !ft.IsExported() && !ft.Anonymous {
+				continue
+			}
+			name, _, skip := jsonFieldName(ft)
+			if skip {
+				continue
+			}
+			if ft.Tag.Get("slog") == "redact" || r.matches(name) || r.computeMayRedact(ft.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactReflect returns a copy of rv with the redacted values
+// replaced by RedactedText and true or rv itself if it contains
+// none. Structs and maps become Maps, slices and arrays become
+// []interface{}.
+func (r *redactRules) redactReflect(rv reflect.Value, depth int) (interface{}, bool) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return rv.Interface(), false
+		}
+		v, changed := r.redactValue(rv.Elem().Interface(), depth)
+		if !changed {
+			return rv.Interface(), false
+		}
+		return v, true
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return rv.Interface(), false
+		}
+		var l []interface{}
+		for i := 0; i < rv.Len(); i++ {
+			v, changed := r.redactValue(rv.Index(i).Interface(), depth)
+			if l == nil {
+				if !changed {
+					continue
+				}
+				l = make([]interface{}, rv.Len())
+				for j := 0; j < i; j++ {
+					l[j] = rv.Index(j).Interface()
+				}
+			}
+			l[i] = v
+		}
+		if l == nil {
+			return rv.Interface(), false
+		}
+		return l, true
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv.Interface(), false
+		}
+		keys := rv.MapKeys()
+		stringKeys := rv.Type().Key().Kind() == reflect.String
+		if !r.mayRedact(rv.Type().Elem()) && !(stringKeys && r.anyKeyMatches(keys)) {
+			return rv.Interface(), false
+		}
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = mapKey(k)
+		}
+		sort.Sort(byName{names, keys})
+		m := make(Map, 0, len(keys))
+		redacted := false
+		for i, k := range keys {
+			var v interface{}
+			var changed bool
+			if stringKeys {
+				v, changed = r.redactField(names[i], rv.MapIndex(k).Interface(), depth)
+			} else {
+				// Only string keys are names.
+				v, changed = r.redactValue(rv.MapIndex(k).Interface(), depth)
+			}
+			redacted = redacted || changed
+			m = append(m, F(names[i], v))
+		}
+		if !redacted {
+			return rv.Interface(), false
+		}
+		return m, true
+	case reflect.Struct:
+		m, changed := r.redactStruct(make(Map, 0, rv.NumField()), rv, depth)
+		if !changed {
+			return rv.Interface(), false
+		}
+		return m, true
+	}
+	return rv.Interface(), false
+}
+
+func (r *redactRules) anyKeyMatches(keys []reflect.Value) bool {
+	for _, k := range keys {
+		if r.matches(k.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// mapKey returns the name of the map key k in JSON. Like
+// encoding/json, string keys are used directly, then keys
+// implementing encoding.TextMarshaler are marshaled and
+// integer keys are formatted.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return ""
+		}
+		b, err := tm.MarshalText()
+		if err == nil {
+			return string(b)
+		}
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+// byName sorts map keys by their names.
+type byName struct {
+	names []string
+	keys  []reflect.Value
+}
+
+func (s byName) Len() int {
+	return len(s.names)
+}
+
+func (s byName) Less(i, j int) bool {
+	return s.names[i] < s.names[j]
+}
+
+func (s byName) Swap(i, j int) {
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// redactStruct appends the exported fields of rv to m
+// following the rules of encoding/json. It reports whether
+// any of them was redacted.
+func (r *redactRules) redactStruct(m Map, rv reflect.Value, depth int) (Map, bool) {
+	redacted := false
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if !ft.IsExported() && !ft.Anonymous {
+			continue
+		}
+		name, omitEmpty, skip := jsonFieldName(ft)
+		if skip {
+			continue
+		}
+		fv := rv.Field(i)
+		if ft.Anonymous && ft.Tag.Get("json") == "" {
+			// Embedded structs are inlined.
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				var changed bool
+				m, changed = r.redactStruct(m, fv, depth)
+				redacted = redacted || changed
+				continue
+			}
+		}
+		if !ft.IsExported() {
+			continue
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if ft.Tag.Get("slog") == "redact" {
+			m = append(m, F(name, RedactedText))
+			redacted = true
+			continue
+		}
+		v, changed := r.redactField(name, fv.Interface(), depth)
+		redacted = redacted || changed
+		m = append(m, F(name, v))
+	}
+	return m, redacted
+}
+
+// jsonFieldName returns the name of the struct field in JSON.
+func jsonFieldName(ft reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := ft.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = ft.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,181 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func init() {
+	slog.RedactNames("*_token", "Authorization")
+}
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" slog:"redact"`
+}
+
+type request struct {
+	Method       string      `json:"method"`
+	Header       http.Header `json:"header"`
+	AccessToken  string      `json:"access_token"`
+	Credentials  *credentials
+	Omitted      string `json:",omitempty"`
+	Ignored      string `json:"-"`
+	notExported  string
+	RefreshToken []string `json:"refresh_token"`
+}
+
+type textKey struct {
+	name string
+}
+
+func (k textKey) MarshalText() ([]byte, error) {
+	return []byte("key-" + k.name), nil
+}
+
+type body struct {
+	Body interface{} `json:"body"`
+	N    int         `json:"n"`
+}
+
+func TestRedaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("redacted", func(t *testing.T) {
+		t.Parallel()
+
+		v := slog.Redacted("secret")
+		assert.Equal(t, "sprint", slog.RedactedText, fmt.Sprint(v))
+		assert.Equal(t, "sprintf", slog.RedactedText, fmt.Sprintf("%#v", v))
+		assert.Equal(t, "resolved", slog.RedactedText, slog.ResolveValue(v))
+		assert.Equal(t, "JSON", indentJSON(t, `{"token": "[REDACTED]"}`), marshalJSON(t, slog.M(
+			slog.F("token", v),
+		)))
+	})
+
+	t.Run("names", func(t *testing.T) {
+		t.Parallel()
+
+		m := slog.M(
+			slog.F("api_token", "secret"),
+			slog.F("AUTHORIZATION", "secret"),
+			slog.Group("req", slog.F("refresh_token", "secret")),
+			slog.F("token", "shown"),
+		)
+		assert.Equal(t, "JSON", indentJSON(t, `{
+			"api_token": "[REDACTED]",
+			"AUTHORIZATION": "[REDACTED]",
+			"req": {"refresh_token": "[REDACTED]"},
+			"token": "shown"
+		}`), marshalJSON(t, m))
+		assert.Equal(t, "map", "secret", m[0].Value)
+	})
+
+	t.Run("structs", func(t *testing.T) {
+		t.Parallel()
+
+		req := &request{
+			Method: "GET",
+			Header: http.Header{
+				"Authorization": []string{"Bearer secret"},
+				"Accept":        []string{"*/*"},
+			},
+			AccessToken: "secret",
+			Credentials: &credentials{
+				User:     "root",
+				Password: "secret",
+			},
+			Ignored:      "secret",
+			notExported:  "secret",
+			RefreshToken: []string{"secret"},
+		}
+		assert.Equal(t, "JSON", indentJSON(t, `{
+			"req": {
+				"method": "GET",
+				"header": {
+					"Accept": ["*/*"],
+					"Authorization": "[REDACTED]"
+				},
+				"access_token": "[REDACTED]",
+				"Credentials": {
+					"user": "root",
+					"password": "[REDACTED]"
+				},
+				"refresh_token": "[REDACTED]"
+			},
+			"creds": [{"user": "u", "password": "[REDACTED]"}]
+		}`), marshalJSON(t, slog.M(
+			slog.F("req", req),
+			slog.F("creds", []credentials{{User: "u", Password: "p"}}),
+		)))
+	})
+
+	t.Run("interfaces", func(t *testing.T) {
+		t.Parallel()
+
+		creds := credentials{User: "root", Password: "secret"}
+		assert.Equal(t, "JSON", indentJSON(t, `{
+			"map": {"c": {"user": "root", "password": "[REDACTED]"}},
+			"body": {"body": {"user": "root", "password": "[REDACTED]"}, "n": 1},
+			"list": [1, {"user": "root", "password": "[REDACTED]"}],
+			"redacted": {"body": "[REDACTED]", "n": 0}
+		}`), marshalJSON(t, slog.M(
+			slog.F("map", map[string]interface{}{"c": creds}),
+			slog.F("body", body{Body: &creds, N: 1}),
+			slog.F("list", []interface{}{1, creds}),
+			slog.F("redacted", body{Body: slog.Redacted("secret")}),
+		)))
+	})
+
+	t.Run("mapKeys", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "JSON", indentJSON(t, `{
+			"ints": {
+				"1": {"user": "a", "password": "[REDACTED]"},
+				"2": {"user": "b", "password": "[REDACTED]"}
+			},
+			"text": {"key-x": {"user": "c", "password": "[REDACTED]"}}
+		}`), marshalJSON(t, slog.M(
+			slog.F("ints", map[int]credentials{
+				2: {User: "b", Password: "secret"},
+				1: {User: "a", Password: "secret"},
+			}),
+			slog.F("text", map[textKey]credentials{
+				{name: "x"}: {User: "c", Password: "secret"},
+			}),
+		)))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		b := &body{}
+		b.Body = b
+		b2, ok := slog.RedactMap(slog.M(slog.F("b", b)))[0].Value.(slog.Map)
+		assert.True(t, "redacted", ok)
+		_, err := json.Marshal(b2)
+		assert.Success(t, "marshal", err)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		m := slog.M(
+			slog.F("a", 1),
+			slog.F("b", []int{1}),
+			slog.F("c", map[string]string{"d": "e"}),
+			slog.Group("g", slog.F("h", "i")),
+			slog.F("j", []interface{}{1, "k"}),
+			slog.F("l", body{Body: credentials{}.User}),
+		)
+		m2 := slog.RedactMap(m)
+		assert.Equal(t, "map", m, m2)
+		assert.True(t, "same", &m[0] == &m2[0])
+	})
+}
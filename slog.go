@@ -16,6 +16,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
@@ -35,13 +36,32 @@ type Sink interface {
 // underlying sinks.
 //
 // It extends the entry with the set fields and names.
+// The entry's fields are nested inside the groups opened
+// with WithGroup.
 func (l Logger) Log(ctx context.Context, e SinkEntry) {
-	if e.Level < l.level {
+	if !l.enabled(ctx, e.Level, e.LoggerNames) {
 		return
 	}
+	e.Fields = l.groups.nest(e.Fields)
+	l.logEntry(ctx, e)
+}
 
+// logEntry is Log without the level check and groups.
+func (l Logger) logEntry(ctx context.Context, e SinkEntry) {
 	e.Fields = l.fields.append(e.Fields)
 	e.LoggerNames = appendNames(l.names, e.LoggerNames...)
+	// Resolve the LogValuers once instead of in every sink.
+	e.Fields = resolveMap(e.Fields)
+
+	for _, h := range l.hooks {
+		var ok bool
+		e, ok = h.ProcessEntry(ctx, e)
+		if !ok {
+			return
+		}
+	}
+	// The fields added by the hooks are subject to the policy too.
+	e.Fields = l.duplicates.apply(e.Fields)
 
 	for _, s := range l.sinks {
 		s.LogEntry(ctx, e)
@@ -59,11 +79,18 @@ func (l Logger) Sync() {
 //
 // Logger is safe for concurrent use.
 type Logger struct {
-	sinks []Sink
-	level Level
+	sinks    []Sink
+	level    Level
+	levelVar *LevelVar
+	rules    levelRules
 
 	names  []string
 	fields Map
+	groups groups
+
+	stacks     stackTraces
+	hooks      []Hook
+	duplicates DuplicateFields
 
 	skip int
 	exit func(int)
@@ -74,8 +101,6 @@ func Make(sinks ...Sink) Logger {
 	return Logger{
 		sinks: sinks,
 		level: LevelInfo,
-
-		exit: os.Exit,
 	}
 }
 
@@ -112,24 +137,44 @@ func (l Logger) Critical(ctx context.Context, msg string, fields ...Field) {
 
 // Fatal logs the msg and fields at LevelFatal.
 //
-// It will then Sync() and os.Exit(1).
+// It will then Sync(), run the hooks registered with
+// RegisterExitHook and os.Exit(1). The exit function
+// can be replaced with WithExit in which case the exit
+// hooks are not run.
 func (l Logger) Fatal(ctx context.Context, msg string, fields ...Field) {
 	l.log(ctx, LevelFatal, msg, fields)
 	l.Sync()
-
-	if l.exit == nil {
-		l.exit = defaultExitFn
-	}
-
-	l.exit(1)
+	l.exitWith(ctx, 1)
 }
 
 // With returns a Logger that prepends the given fields on every
 // logged entry.
 //
 // It will append to any fields already in the Logger.
+// If a group has been opened with WithGroup, the fields
+// are added to it.
 func (l Logger) With(fields ...Field) Logger {
-	l.fields = l.fields.append(fields)
+	if len(l.groups) == 0 {
+		l.fields = l.fields.append(fields)
+		return l
+	}
+	l.groups = l.groups.with(fields)
+	return l
+}
+
+// WithGroup returns a Logger that nests all fields added
+// with With and all fields of logged entries under name.
+// Groups may be nested by calling WithGroup multiple times.
+//
+// Fields in the context are not nested.
+// Groups without any fields are omitted.
+func (l Logger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+	gs := make(groups, 0, len(l.groups)+1)
+	gs = append(gs, l.groups...)
+	l.groups = append(gs, group{name: name})
 	return l
 }
 
@@ -142,12 +187,43 @@ func (l Logger) Named(name string) Logger {
 
 // Leveled returns a Logger that only logs entries
 // equal to or above the given level.
+//
+// It replaces any LevelVar set with LeveledVar.
 func (l Logger) Leveled(level Level) Logger {
 	l.level = level
+	l.levelVar = nil
 	l.sinks = append([]Sink(nil), l.sinks...)
 	return l
 }
 
+// LeveledVar returns a Logger that only logs entries
+// equal to or above the current level of v.
+//
+// The Logger and all Loggers derived from it share v
+// so changing it with v.Set takes effect on all of them
+// immediately.
+func (l Logger) LeveledVar(v *LevelVar) Logger {
+	l.levelVar = v
+	l.sinks = append([]Sink(nil), l.sinks...)
+	return l
+}
+
+// enabled reports whether an entry at level with the given names
+// in addition to the Logger's names should be logged.
+func (l Logger) enabled(ctx context.Context, level Level, names []string) bool {
+	if ctxLevel, ok := levelFromContext(ctx); ok {
+		return level >= ctxLevel
+	}
+	return level >= l.levelFor(names)
+}
+
+func (l Logger) minLevel() Level {
+	if l.levelVar != nil {
+		return l.levelVar.Level()
+	}
+	return l.level
+}
+
 // AppendSinks appends the sinks to the set sink
 // targets on the logger.
 func (l Logger) AppendSinks(s ...Sink) Logger {
@@ -155,9 +231,43 @@ func (l Logger) AppendSinks(s ...Sink) Logger {
 	return l
 }
 
+// Enabled reports whether l would log an entry at level
+// with ctx.
+//
+// It takes into account the level of l, any level override
+// in ctx and the levels of sinks implementing LevelEnabler.
+// It can be used to avoid computing expensive fields for
+// entries that would be dropped.
+func (l Logger) Enabled(ctx context.Context, level Level) bool {
+	if !l.enabled(ctx, level, nil) {
+		return false
+	}
+	for _, s := range l.sinks {
+		le, ok := s.(LevelEnabler)
+		if !ok || le.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// LevelEnabler may be implemented by a Sink to report whether
+// it logs entries at level. A Logger does not build entries
+// that none of its sinks log.
+type LevelEnabler interface {
+	Enabled(ctx context.Context, level Level) bool
+}
+
+var _ LevelEnabler = Logger{}
+
 func (l Logger) log(ctx context.Context, level Level, msg string, fields []Field) {
-	ent := l.entry(ctx, level, msg, fields)
-	l.Log(ctx, ent)
+	// Check the level before building the entry so that
+	// dropped entries do not allocate or walk the stack.
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	ent := l.entry(ctx, level, msg, l.groups.nest(fields))
+	l.logEntry(ctx, ent)
 }
 
 func (l Logger) entry(ctx context.Context, level Level, msg string, fields Map) SinkEntry {
@@ -169,6 +279,9 @@ func (l Logger) entry(ctx context.Context, level Level, msg string, fields Map)
 		SpanContext: trace.SpanContextFromContext(ctx),
 	}
 	ent = ent.fillLoc(l.skip + 3)
+	if st := l.stacks.fields(level, l.skip+3, ""); len(st) > 0 {
+		ent.Fields = ent.Fields.append(st)
+	}
 	return ent
 }
 
@@ -241,7 +354,50 @@ func M(fs ...Field) Map {
 	return fs
 }
 
+// Group is a convenience constructor for a Field
+// whose value is a Map of the given fields.
+//
+// Sinks render groups as nested objects or, if they
+// cannot nest values, with the group name prefixed to
+// the keys of the fields such as db.query.
+func Group(name string, fields ...Field) Field {
+	return F(name, M(fields...))
+}
+
+type group struct {
+	name   string
+	fields Map
+}
+
+// groups are the groups opened on a Logger
+// from the outermost to the innermost.
+type groups []group
+
+// with adds fields to the innermost group.
+func (gs groups) with(fields Map) groups {
+	gs = append(groups(nil), gs...)
+	g := &gs[len(gs)-1]
+	g.fields = g.fields.append(fields)
+	return gs
+}
+
+// nest nests fields inside gs.
+func (gs groups) nest(fields Map) Map {
+	for i := len(gs) - 1; i >= 0; i-- {
+		g := gs[i]
+		inner := g.fields.append(fields)
+		fields = nil
+		if len(inner) > 0 {
+			fields = Map{Group(g.name, inner...)}
+		}
+	}
+	return fields
+}
+
 // Error is the standard key used for logging a Go error value.
+//
+// The fields of the errors in the chain of err implementing
+// LogFielder are written by the sinks along with it.
 func Error(err error) Field {
 	return F("error", err)
 }
@@ -268,6 +424,57 @@ func With(ctx context.Context, fields ...Field) context.Context {
 	return fieldsWithContext(ctx, f2)
 }
 
+type loggerKey struct{}
+
+// IntoContext returns a context that carries l.
+//
+// The Logger can be retrieved with FromContext, allowing
+// code deep in the call stack to log through the caller's
+// sinks, names, fields and level.
+func IntoContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx with IntoContext.
+//
+// If there is none, it returns Default(). Use FromContextOr
+// to configure the fallback.
+func FromContext(ctx context.Context) Logger {
+	l, ok := ctx.Value(loggerKey{}).(Logger)
+	if !ok {
+		return Default()
+	}
+	return l
+}
+
+// FromContextOr returns the Logger stored in ctx with IntoContext
+// or fallback if there is none.
+func FromContextOr(ctx context.Context, fallback Logger) Logger {
+	l, ok := ctx.Value(loggerKey{}).(Logger)
+	if !ok {
+		return fallback
+	}
+	return l
+}
+
+type levelKey struct{}
+
+func levelFromContext(ctx context.Context) (Level, bool) {
+	l, ok := ctx.Value(levelKey{}).(Level)
+	return l, ok
+}
+
+// WithLevel returns a context that overrides the level of
+// every Logger it is logged with.
+//
+// This allows logging a single request at LevelDebug
+// without changing the level of the rest of the traffic.
+// It takes precedence over Logger.Leveled, Logger.LeveledVar
+// and Logger.LeveledRules.
+func WithLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, levelKey{}, level)
+}
+
 // SinkEntry represents the structure of a log entry.
 // It is the argument to the sink when logging.
 type SinkEntry struct {
@@ -288,46 +495,73 @@ type SinkEntry struct {
 }
 
 // Level represents a log level.
+//
+// Levels were numbered 0 to 5 before custom levels could be
+// registered and are now spaced 10 apart. Code that stores levels
+// as integers, converts integers with slog.Level(n) or derives
+// levels with arithmetic such as LevelInfo+1 must be updated;
+// use the constants, ParseLevel or the level's text form instead.
 type Level int
 
 // The supported log levels.
 //
+// The levels are spaced apart so that additional levels
+// can be registered in between them with RegisterLevel.
+// Their values changed from 0..5 to 0..50, see Level.
+//
 // The default level is Info.
 const (
 	// LevelDebug is used for development and debugging messages.
-	LevelDebug Level = iota
+	LevelDebug Level = 0
 
 	// LevelInfo is used for normal informational messages.
-	LevelInfo
+	LevelInfo Level = 10
 
 	// LevelWarn is used when something has possibly gone wrong.
-	LevelWarn
+	LevelWarn Level = 20
 
 	// LevelError is used when something has certainly gone wrong.
-	LevelError
+	LevelError Level = 30
 
 	// LevelCritical is used when when something has gone wrong and should
 	// be immediately investigated.
-	LevelCritical
+	LevelCritical Level = 40
 
 	// LevelFatal is used when the process is about to exit due to an error.
-	LevelFatal
+	LevelFatal Level = 50
 )
 
-var levelStrings = map[Level]string{
-	LevelDebug:    "DEBUG",
-	LevelInfo:     "INFO",
-	LevelWarn:     "WARN",
-	LevelError:    "ERROR",
-	LevelCritical: "CRITICAL",
-	LevelFatal:    "FATAL",
-}
-
 // String implements fmt.Stringer.
+//
+// Registered levels return their name.
 func (l Level) String() string {
-	s, ok := levelStrings[l]
+	c, ok := LookupLevel(l)
 	if !ok {
 		return fmt.Sprintf("slog.Level(%v)", int(l))
 	}
-	return s
+	return c.Name
+}
+
+// LevelVar is a Level that can be read and changed concurrently.
+// It allows the level of a running Logger to be adjusted.
+// See Logger.LeveledVar.
+//
+// The zero value is LevelDebug.
+type LevelVar struct {
+	v atomic.Int64
+}
+
+// Level returns the current level.
+func (v *LevelVar) Level() Level {
+	return Level(v.v.Load())
+}
+
+// Set changes the level.
+func (v *LevelVar) Set(l Level) {
+	v.v.Store(int64(l))
+}
+
+// String implements fmt.Stringer.
+func (v *LevelVar) String() string {
+	return fmt.Sprintf("LevelVar(%v)", v.Level())
 }
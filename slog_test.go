@@ -128,10 +128,8 @@ func TestLogger(t *testing.T) {
 		t.Parallel()
 
 		s := &fakeSink{}
-		l := slog.Make(s)
-
 		exits := 0
-		l.SetExit(func(int) {
+		l := slog.Make(s).WithExit(func(int) {
 			exits++
 		})
 
@@ -153,6 +151,129 @@ func TestLogger(t *testing.T) {
 		assert.Equal(t, "level", slog.LevelFatal, s.entries[5].Level)
 		assert.Equal(t, "exits", 1, exits)
 	})
+
+	t.Run("leveledSinks", func(t *testing.T) {
+		t.Parallel()
+
+		debug := &fakeSink{}
+		info := &fakeSink{}
+		errs := &fakeSink{}
+		l := slog.Make(
+			slog.LeveledSink(debug, slog.LevelDebug),
+			slog.LeveledSink(info, slog.LevelInfo),
+			slog.LeveledSink(errs, slog.LevelError),
+		).Leveled(slog.LevelDebug)
+
+		l.Debug(bg, "")
+		l.Info(bg, "")
+		l.Error(bg, "")
+
+		assert.Len(t, "debug entries", 3, debug.entries)
+		assert.Len(t, "info entries", 2, info.entries)
+		assert.Len(t, "error entries", 1, errs.entries)
+		assert.Equal(t, "syncs", 1, errs.syncs)
+
+		l = slog.Make(slog.LeveledSink(info, slog.LevelInfo)).Leveled(slog.LevelDebug)
+		assert.False(t, "enabled", l.Enabled(bg, slog.LevelDebug))
+		assert.True(t, "enabled", l.Enabled(bg, slog.LevelInfo))
+		assert.False(t, "enabled", slog.Make(slog.LeveledSink(&leveledFakeSink{level: slog.LevelWarn}, slog.LevelInfo)).Enabled(bg, slog.LevelInfo))
+	})
+
+	t.Run("levelVar", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		var lv slog.LevelVar
+		lv.Set(slog.LevelInfo)
+		l := slog.Make(s).LeveledVar(&lv)
+		l2 := l.Named("derived").With(slog.F("a", 1))
+
+		l.Debug(bg, "")
+		l2.Debug(bg, "")
+		lv.Set(slog.LevelDebug)
+		l.Debug(bg, "")
+		l2.Debug(bg, "")
+		lv.Set(slog.LevelError)
+		l2.Warn(bg, "")
+		l2.Leveled(slog.LevelWarn).Warn(bg, "")
+
+		assert.Len(t, "entries", 3, s.entries)
+		assert.Equal(t, "level var", "LevelVar(ERROR)", lv.String())
+	})
+
+	t.Run("contextLevel", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).Named("http").LeveledRules(slog.LevelRule{Pattern: "http", Level: slog.LevelWarn})
+		ctx := slog.WithLevel(bg, slog.LevelDebug)
+
+		l.Debug(bg, "dropped")
+		l.Info(bg, "dropped")
+		l.Debug(ctx, "debug")
+		l.Named("child").Info(ctx, "info")
+		l.Info(slog.WithLevel(ctx, slog.LevelError), "dropped")
+
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Equal(t, "msg", "debug", s.entries[0].Message)
+		assert.Equal(t, "msg", "info", s.entries[1].Message)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).With(slog.F("top", 1)).WithGroup("db").With(slog.F("name", "pg"))
+		ctx := slog.With(bg, slog.F("ctx", 2))
+
+		l.WithGroup("query").Info(ctx, "", slog.F("rows", 3))
+		l.WithGroup("empty").Info(bg, "")
+		l.Log(bg, slog.SinkEntry{Level: slog.LevelInfo, Fields: slog.M(slog.F("entry", 4))})
+
+		assert.Len(t, "entries", 3, s.entries)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("top", 1),
+			slog.F("ctx", 2),
+			slog.Group("db",
+				slog.F("name", "pg"),
+				slog.Group("query",
+					slog.F("rows", 3),
+				),
+			),
+		), s.entries[0].Fields)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("top", 1),
+			slog.Group("db",
+				slog.F("name", "pg"),
+			),
+		), s.entries[1].Fields)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("top", 1),
+			slog.Group("db",
+				slog.F("name", "pg"),
+				slog.F("entry", 4),
+			),
+		), s.entries[2].Fields)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).Named("svc").With(slog.F("a", 1)).Leveled(slog.LevelWarn)
+		ctx := slog.IntoContext(bg, l)
+
+		slog.FromContext(ctx).Info(ctx, "dropped")
+		slog.FromContext(ctx).Warn(ctx, "logged")
+
+		fallback := &fakeSink{}
+		slog.FromContextOr(bg, slog.Make(fallback)).Info(bg, "fallback")
+
+		assert.Len(t, "entries", 1, s.entries)
+		assert.Equal(t, "names", []string{"svc"}, s.entries[0].LoggerNames)
+		assert.Equal(t, "fields", slog.M(slog.F("a", 1)), s.entries[0].Fields)
+		assert.Len(t, "fallback entries", 1, fallback.entries)
+	})
 }
 
 func TestLevel_String(t *testing.T) {
@@ -160,3 +281,35 @@ func TestLevel_String(t *testing.T) {
 
 	assert.Equal(t, "level string", "slog.Level(12)", slog.Level(12).String())
 }
+
+type leveledFakeSink struct {
+	fakeSink
+	level slog.Level
+}
+
+func (s *leveledFakeSink) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= s.level
+}
+
+func TestLogger_Enabled(t *testing.T) {
+	// This can't be parallel since it measures allocations.
+
+	s := &leveledFakeSink{level: slog.LevelWarn}
+	l := slog.Make(s).Leveled(slog.LevelDebug)
+
+	assert.False(t, "no sinks", slog.Make().Enabled(bg, slog.LevelFatal))
+	assert.False(t, "logger level", slog.Make(s).Enabled(bg, slog.LevelDebug))
+	assert.False(t, "sink level", l.Enabled(bg, slog.LevelInfo))
+	assert.True(t, "sink level", l.Enabled(bg, slog.LevelWarn))
+	assert.True(t, "any sink", l.AppendSinks(&fakeSink{}).Enabled(bg, slog.LevelInfo))
+	assert.False(t, "context level", l.Enabled(slog.WithLevel(bg, slog.LevelError), slog.LevelWarn))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info(bg, "dropped", slog.F("a", 1), slog.F("b", "b"))
+	})
+	assert.Equal(t, "allocs", 0.0, allocs)
+	assert.Len(t, "entries", 0, s.entries)
+
+	l.Warn(bg, "logged")
+	assert.Len(t, "entries", 1, s.entries)
+}
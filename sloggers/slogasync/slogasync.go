@@ -0,0 +1,249 @@
+// Package slogasync contains a slog.Sink that writes entries
+// to another sink from a separate goroutine so that slow
+// writers do not block the logging goroutines.
+package slogasync // import "cdr.dev/slog/v3/sloggers/slogasync"
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cdr.dev/slog/v3"
+)
+
+// Policy decides what happens to entries logged
+// while the queue is full.
+type Policy int
+
+const (
+	// Block blocks the logging goroutine until
+	// there is room in the queue.
+	Block Policy = iota
+	// DropNewest drops the entry being logged.
+	DropNewest
+	// DropOldest drops the oldest entry in the queue
+	// to make room for the one being logged.
+	DropOldest
+)
+
+// Options configures the Sink.
+type Options struct {
+	// QueueSize is the number of entries that can be queued.
+	// It defaults to 1024.
+	QueueSize int
+
+	// Policy is applied when the queue is full.
+	// It defaults to Block.
+	Policy Policy
+
+	// WarnInterval is the interval at which an entry warning
+	// about the entries dropped in the meantime is written.
+	// It defaults to 10 seconds.
+	WarnInterval time.Duration
+}
+
+// Sink is an asynchronous slog.Sink.
+// It must be closed with Close to stop its goroutine.
+type Sink struct {
+	s    slog.Sink
+	opts Options
+
+	queue chan queued
+	done  chan struct{}
+
+	// mu guards closing the queue.
+	mu     sync.RWMutex
+	closed bool
+
+	// pushMu makes the entries enter the queue in the order
+	// of their sequence numbers.
+	pushMu sync.Mutex
+	// seq is the sequence number of the last queued entry.
+	seq atomic.Uint64
+
+	// processedMu guards processed which is the sequence number
+	// of the last entry either written or dropped after it was
+	// queued.
+	processedMu   sync.Mutex
+	processedCond *sync.Cond
+	processed     uint64
+
+	dropped atomic.Uint64
+	total   atomic.Uint64
+}
+
+type queued struct {
+	ctx context.Context
+	ent slog.SinkEntry
+	seq uint64
+}
+
+var _ slog.LevelEnabler = &Sink{}
+
+// New returns a Sink that queues entries and writes them
+// to s from a single goroutine.
+//
+// opts may be nil to use the defaults.
+func New(s slog.Sink, opts *Options) *Sink {
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.QueueSize <= 0 {
+		o.QueueSize = 1024
+	}
+	if o.WarnInterval <= 0 {
+		o.WarnInterval = 10 * time.Second
+	}
+
+	as := &Sink{
+		s:     s,
+		opts:  o,
+		queue: make(chan queued, o.QueueSize),
+		done:  make(chan struct{}),
+	}
+	as.processedCond = sync.NewCond(&as.processedMu)
+	go as.run()
+	return as
+}
+
+func (as *Sink) run() {
+	defer close(as.done)
+
+	t := time.NewTicker(as.opts.WarnInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case q, ok := <-as.queue:
+			if !ok {
+				return
+			}
+			as.s.LogEntry(q.ctx, q.ent)
+			as.markProcessed(q.seq)
+		case <-t.C:
+			as.warnDropped()
+		}
+	}
+}
+
+func (as *Sink) markProcessed(seq uint64) {
+	as.processedMu.Lock()
+	as.processed = seq
+	as.processedCond.Broadcast()
+	as.processedMu.Unlock()
+}
+
+// LogEntry queues ent. Entries logged after Close
+// are written synchronously.
+func (as *Sink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	if as.closed {
+		as.s.LogEntry(ctx, ent)
+		return
+	}
+
+	as.pushMu.Lock()
+	defer as.pushMu.Unlock()
+
+	q := queued{ctx: ctx, ent: ent, seq: as.seq.Load() + 1}
+	switch as.opts.Policy {
+	case DropNewest:
+		select {
+		case as.queue <- q:
+		default:
+			as.drop()
+			return
+		}
+	case DropOldest:
+	push:
+		for {
+			select {
+			case as.queue <- q:
+				break push
+			default:
+			}
+			select {
+			case <-as.queue:
+				// The writer marks q as processed which
+				// covers the dropped entry as well.
+				as.drop()
+			default:
+			}
+		}
+	default:
+		as.queue <- q
+	}
+	as.seq.Store(q.seq)
+}
+
+func (as *Sink) drop() {
+	as.dropped.Add(1)
+	as.total.Add(1)
+}
+
+// warnDropped writes a warning with the number of entries
+// dropped since the last warning.
+func (as *Sink) warnDropped() {
+	n := as.dropped.Swap(0)
+	if n == 0 {
+		return
+	}
+	as.s.LogEntry(context.Background(), slog.SinkEntry{
+		Time:    time.Now().UTC(),
+		Level:   slog.LevelWarn,
+		Message: "dropped log entries as the queue was full",
+		Fields: slog.M(
+			slog.F("dropped", n),
+			slog.F("queue_size", as.opts.QueueSize),
+		),
+	})
+}
+
+// Dropped returns the total number of entries dropped
+// because the queue was full.
+func (as *Sink) Dropped() uint64 {
+	return as.total.Load()
+}
+
+func (as *Sink) Enabled(ctx context.Context, level slog.Level) bool {
+	le, ok := as.s.(slog.LevelEnabler)
+	return !ok || le.Enabled(ctx, level)
+}
+
+// Sync waits until every entry queued before the call has
+// been written and then syncs the underlying sink.
+func (as *Sink) Sync() {
+	// The queue is FIFO and entries are queued in the order of
+	// their sequence numbers so once the entry with the last
+	// sequence number is processed so are all the previous ones.
+	n := as.seq.Load()
+	as.processedMu.Lock()
+	for as.processed < n {
+		as.processedCond.Wait()
+	}
+	as.processedMu.Unlock()
+
+	as.warnDropped()
+	as.s.Sync()
+}
+
+// Close writes the queued entries, stops the goroutine
+// and syncs the underlying sink.
+func (as *Sink) Close() {
+	as.mu.Lock()
+	if as.closed {
+		as.mu.Unlock()
+		return
+	}
+	as.closed = true
+	close(as.queue)
+	as.mu.Unlock()
+
+	<-as.done
+	as.warnDropped()
+	as.s.Sync()
+}
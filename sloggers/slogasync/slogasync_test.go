@@ -0,0 +1,173 @@
+package slogasync_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/sloggers/slogasync"
+)
+
+var bg = context.Background()
+
+// blockingSink blocks writing the entries until release is closed.
+type blockingSink struct {
+	started chan struct{}
+	release chan struct{}
+
+	mu      sync.Mutex
+	entries []slog.SinkEntry
+	syncs   int
+}
+
+func newBlockingSink() *blockingSink {
+	return &blockingSink{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *blockingSink) LogEntry(_ context.Context, e slog.SinkEntry) {
+	s.started <- struct{}{}
+	<-s.release
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+func (s *blockingSink) Sync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncs++
+}
+
+func (s *blockingSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var msgs []string
+	for _, e := range s.entries {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sync", func(t *testing.T) {
+		t.Parallel()
+
+		s := newBlockingSink()
+		close(s.release)
+		as := slogasync.New(s, nil)
+		defer as.Close()
+
+		l := slog.Make(as)
+		l.Info(bg, "1")
+		l.Info(bg, "2")
+		l.Error(bg, "3")
+
+		assert.Equal(t, "messages", []string{"1", "2", "3"}, s.messages())
+		assert.Equal(t, "syncs", 1, s.syncs)
+	})
+
+	t.Run("concurrentSync", func(t *testing.T) {
+		t.Parallel()
+
+		s := newBlockingSink()
+		close(s.release)
+		as := slogasync.New(s, &slogasync.Options{QueueSize: 4})
+		defer as.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				msg := strconv.Itoa(i)
+				as.LogEntry(bg, slog.SinkEntry{Message: msg})
+				as.Sync()
+				for _, m := range s.messages() {
+					if m == msg {
+						return
+					}
+				}
+				t.Errorf("entry %v not written after Sync", msg)
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("dropNewest", func(t *testing.T) {
+		t.Parallel()
+
+		s := newBlockingSink()
+		as := slogasync.New(s, &slogasync.Options{
+			QueueSize: 1,
+			Policy:    slogasync.DropNewest,
+		})
+		defer as.Close()
+
+		as.LogEntry(bg, slog.SinkEntry{Message: "0"})
+		<-s.started
+		as.LogEntry(bg, slog.SinkEntry{Message: "1"})
+		as.LogEntry(bg, slog.SinkEntry{Message: "2"})
+		as.LogEntry(bg, slog.SinkEntry{Message: "3"})
+		close(s.release)
+		as.Sync()
+
+		assert.Equal(t, "dropped", uint64(2), as.Dropped())
+		assert.Equal(t, "messages", []string{"0", "1", "dropped log entries as the queue was full"}, s.messages())
+		assert.Equal(t, "fields", slog.M(
+			slog.F("dropped", uint64(2)),
+			slog.F("queue_size", 1),
+		), s.entries[2].Fields)
+	})
+
+	t.Run("dropOldest", func(t *testing.T) {
+		t.Parallel()
+
+		s := newBlockingSink()
+		as := slogasync.New(s, &slogasync.Options{
+			QueueSize: 1,
+			Policy:    slogasync.DropOldest,
+		})
+		defer as.Close()
+
+		as.LogEntry(bg, slog.SinkEntry{Message: "0"})
+		<-s.started
+		as.LogEntry(bg, slog.SinkEntry{Message: "1"})
+		as.LogEntry(bg, slog.SinkEntry{Message: "2"})
+		as.LogEntry(bg, slog.SinkEntry{Message: "3"})
+		close(s.release)
+		as.Sync()
+
+		assert.Equal(t, "dropped", uint64(2), as.Dropped())
+		assert.Equal(t, "messages", []string{"0", "3", "dropped log entries as the queue was full"}, s.messages())
+	})
+
+	t.Run("close", func(t *testing.T) {
+		t.Parallel()
+
+		s := newBlockingSink()
+		close(s.release)
+		as := slogasync.New(s, nil)
+		as.LogEntry(bg, slog.SinkEntry{Message: "queued"})
+		as.Close()
+		as.Close()
+		as.LogEntry(bg, slog.SinkEntry{Message: "after close"})
+
+		assert.Equal(t, "messages", []string{"queued", "after close"}, s.messages())
+		assert.Equal(t, "syncs", 1, s.syncs)
+	})
+}
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,345 @@
+// Package slogdedup contains a slog.Sink that suppresses
+// duplicate entries such as those of retry loops.
+//
+// Entries are duplicates if they have the same level, message,
+// logger names and field values. Only the first entry of a
+// burst of duplicates is forwarded. The rest are reported with
+// summary entries with the message, level, names and fields of
+// the first one and the repeated, first_seen and last_seen
+// fields.
+package slogdedup // import "cdr.dev/slog/v3/sloggers/slogdedup"
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cdr.dev/slog/v3"
+)
+
+// Options configures the deduplication.
+type Options struct {
+	// Window is the time after the last duplicate at which a
+	// burst ends. Entries in between do not end the burst.
+	//
+	// If zero, only consecutive duplicates are suppressed and
+	// a burst ends with the first different entry.
+	Window time.Duration
+}
+
+// Sink returns a slog.Sink that forwards entries to s
+// without their duplicates.
+//
+// The summary of a burst is written when it ends, as determined
+// by a timer or by the entries logged after it. Sync also writes
+// the summaries of the bursts still going on without ending them
+// but at most once per Window for each burst so that the Sync
+// of Logger.Error does not defeat the deduplication. The repeated
+// field of a summary counts the duplicates since the previous one.
+func Sink(s slog.Sink, opts Options) slog.Sink {
+	return makeSink(s, opts, time.Now, func(d time.Duration, fn func()) {
+		time.AfterFunc(d, fn)
+	})
+}
+
+func makeSink(s slog.Sink, opts Options, now func() time.Time, afterFunc func(time.Duration, func())) *dedupSink {
+	return &dedupSink{
+		s:         s,
+		opts:      opts,
+		now:       now,
+		afterFunc: afterFunc,
+		bursts:    make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+type burst struct {
+	key      string
+	ent      slog.SinkEntry
+	ctx      context.Context
+	lastSeen time.Time
+	// repeated counts the duplicates since the last summary.
+	repeated int
+	// reported is the time of the last summary or
+	// of the first entry.
+	reported time.Time
+}
+
+type dedupSink struct {
+	s         slog.Sink
+	opts      Options
+	now       func() time.Time
+	afterFunc func(time.Duration, func())
+
+	mu     sync.Mutex
+	bursts map[string]*list.Element
+	// order holds the bursts from the least to the most
+	// recently seen so that expiring them stops at the
+	// first one still in its window.
+	order *list.List
+	// timer is set while a timer writing the summaries
+	// of the ended bursts is pending.
+	timer bool
+}
+
+var _ slog.LevelEnabler = &dedupSink{}
+
+func (s *dedupSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	k := key(ent)
+
+	s.mu.Lock()
+	var summaries []summary
+	if s.opts.Window > 0 {
+		summaries = s.expire(ent.Time)
+	}
+	e, ok := s.bursts[k]
+	if ok {
+		b := e.Value.(*burst)
+		b.repeated++
+		b.lastSeen = ent.Time
+		s.order.MoveToBack(e)
+		if s.opts.Window > 0 && !s.timer {
+			s.timer = true
+			s.afterFunc(s.opts.Window, s.onTimer)
+		}
+	} else {
+		if s.opts.Window <= 0 {
+			// Only the last entry is tracked.
+			summaries = s.expire(time.Time{})
+		}
+		s.bursts[k] = s.order.PushBack(&burst{
+			key:      k,
+			ent:      ent,
+			ctx:      ctx,
+			lastSeen: ent.Time,
+			reported: ent.Time,
+		})
+	}
+	s.mu.Unlock()
+
+	s.write(summaries)
+	if !ok {
+		s.s.LogEntry(ctx, ent)
+	}
+}
+
+// onTimer writes the summaries of the bursts that ended and
+// sets a timer for the next one with duplicates to report.
+func (s *dedupSink) onTimer() {
+	s.mu.Lock()
+	now := s.now()
+	summaries := s.expire(now)
+	s.timer = false
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		b := e.Value.(*burst)
+		if b.repeated > 0 {
+			s.timer = true
+			s.afterFunc(b.lastSeen.Add(s.opts.Window).Sub(now), s.onTimer)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	s.write(summaries)
+}
+
+type summary struct {
+	ctx context.Context
+	ent slog.SinkEntry
+}
+
+func (s *dedupSink) write(summaries []summary) {
+	for _, sum := range summaries {
+		s.s.LogEntry(sum.ctx, sum.ent)
+	}
+}
+
+// expire removes the bursts whose window has passed at now and
+// returns the summaries of the ones with duplicates in the order
+// they were last seen. The zero time expires all of them.
+// s.mu must be held.
+func (s *dedupSink) expire(now time.Time) []summary {
+	var summaries []summary
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		b := e.Value.(*burst)
+		if !now.IsZero() && now.Sub(b.lastSeen) < s.opts.Window {
+			break
+		}
+		s.order.Remove(e)
+		delete(s.bursts, b.key)
+		if b.repeated > 0 {
+			summaries = append(summaries, b.summary(now))
+		}
+	}
+	return summaries
+}
+
+// summary returns the summary of the duplicates since the
+// last one and resets their count. s.mu must be held.
+func (b *burst) summary(now time.Time) summary {
+	ent := b.ent
+	ent.Time = b.lastSeen
+	ent.Fields = append(ent.Fields[:len(ent.Fields):len(ent.Fields)],
+		slog.F("repeated", b.repeated),
+		slog.F("first_seen", b.ent.Time),
+		slog.F("last_seen", b.lastSeen),
+	)
+	b.repeated = 0
+	b.reported = now
+	return summary{ctx: b.ctx, ent: ent}
+}
+
+// key returns the string identifying the duplicates of ent.
+// The field values are written by content rather than encoded
+// as JSON which would resolve and redact them for entries
+// that are forwarded anyway.
+func key(ent slog.SinkEntry) string {
+	var sb strings.Builder
+	sb.WriteString(ent.Level.String())
+	sb.WriteByte(0)
+	sb.WriteString(ent.Message)
+	sb.WriteByte(0)
+	sb.WriteString(strings.Join(ent.LoggerNames, "."))
+	sb.WriteByte(0)
+	writeFields(&sb, ent.Fields, 0)
+	return sb.String()
+}
+
+// maxKeyDepth bounds the recursion into values so that
+// cyclic values terminate.
+const maxKeyDepth = 32
+
+func writeFields(sb *strings.Builder, m slog.Map, depth int) {
+	for _, f := range m {
+		sb.WriteString(f.Name)
+		sb.WriteByte('=')
+		writeValue(sb, f.Value, depth)
+		sb.WriteByte(0)
+	}
+}
+
+func writeValue(sb *strings.Builder, v interface{}, depth int) {
+	switch v := v.(type) {
+	case nil:
+		sb.WriteString("nil")
+		return
+	case slog.Map:
+		sb.WriteByte('{')
+		writeFields(sb, v, depth+1)
+		sb.WriteByte('}')
+		return
+	case string:
+		sb.WriteString(strconv.Quote(v))
+		return
+	case error:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		// Errors are often wrapped anew on every attempt.
+		fmt.Fprintf(sb, "%T(%q)", v, v.Error())
+		return
+	}
+	writeReflect(sb, reflect.ValueOf(v), depth)
+}
+
+// writeReflect writes the content of rv. Pointers are followed
+// so that equal values behind different pointers are the same.
+func writeReflect(sb *strings.Builder, rv reflect.Value, depth int) {
+	if depth > maxKeyDepth {
+		sb.WriteString("...")
+		return
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		sb.WriteByte('&')
+		if rv.Elem().CanInterface() {
+			writeValue(sb, rv.Elem().Interface(), depth+1)
+			return
+		}
+		writeReflect(sb, rv.Elem(), depth+1)
+	case reflect.Struct:
+		sb.WriteString(rv.Type().String())
+		sb.WriteByte('{')
+		for i := 0; i < rv.NumField(); i++ {
+			sb.WriteString(rv.Type().Field(i).Name)
+			sb.WriteByte(':')
+			fv := rv.Field(i)
+			if fv.CanInterface() {
+				writeValue(sb, fv.Interface(), depth+1)
+			} else {
+				writeReflect(sb, fv, depth+1)
+			}
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('}')
+	case reflect.Map:
+		keys := make([]string, 0, rv.Len())
+		vals := make(map[string]reflect.Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			var kb strings.Builder
+			writeReflect(&kb, iter.Key(), depth+1)
+			keys = append(keys, kb.String())
+			vals[kb.String()] = iter.Value()
+		}
+		sort.Strings(keys)
+		sb.WriteByte('{')
+		for _, k := range keys {
+			sb.WriteString(k)
+			sb.WriteByte(':')
+			writeReflect(sb, vals[k], depth+1)
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		sb.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			writeReflect(sb, rv.Index(i), depth+1)
+			sb.WriteByte(',')
+		}
+		sb.WriteByte(']')
+	case reflect.String:
+		sb.WriteString(strconv.Quote(rv.String()))
+	case reflect.Invalid:
+		sb.WriteString("nil")
+	default:
+		// Numbers, booleans and the identity of
+		// channels and functions.
+		fmt.Fprintf(sb, "%v", rv)
+	}
+}
+
+func (s *dedupSink) Enabled(ctx context.Context, level slog.Level) bool {
+	le, ok := s.s.(slog.LevelEnabler)
+	return !ok || le.Enabled(ctx, level)
+}
+
+// Sync writes the summaries of the duplicates not reported
+// within the last Window without ending their bursts and
+// syncs the underlying sink.
+func (s *dedupSink) Sync() {
+	s.mu.Lock()
+	now := s.now()
+	var summaries []summary
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		b := e.Value.(*burst)
+		if b.repeated > 0 && now.Sub(b.reported) >= s.opts.Window {
+			summaries = append(summaries, b.summary(now))
+		}
+	}
+	s.mu.Unlock()
+
+	s.write(summaries)
+	s.s.Sync()
+}
@@ -0,0 +1,200 @@
+package slogdedup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/internal/sinktest"
+	"cdr.dev/slog/v3/sloggers/slogdedup"
+)
+
+var bg = context.Background()
+
+var t0 = time.Date(2000, time.February, 5, 4, 4, 4, 0, time.UTC)
+
+func entry(sec int, msg string, fields ...slog.Field) slog.SinkEntry {
+	return slog.SinkEntry{
+		Time:        t0.Add(time.Duration(sec) * time.Second),
+		Level:       slog.LevelWarn,
+		Message:     msg,
+		LoggerNames: []string{"conn"},
+		Fields:      fields,
+	}
+}
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("consecutive", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		ds := slogdedup.Sink(s, slogdedup.Options{})
+		ds.LogEntry(bg, entry(0, "reconnecting", slog.F("attempt", "x")))
+		ds.LogEntry(bg, entry(1, "reconnecting", slog.F("attempt", "x")))
+		ds.LogEntry(bg, entry(2, "reconnecting", slog.F("attempt", "x")))
+		ds.LogEntry(bg, entry(3, "reconnecting", slog.F("attempt", "y")))
+		ds.LogEntry(bg, entry(4, "reconnecting", slog.F("attempt", "x")))
+
+		assert.Len(t, "entries", 4, s.Entries)
+		assert.Equal(t, "fields", slog.M(slog.F("attempt", "x")), s.Entries[0].Fields)
+		assert.Equal(t, "summary", slog.SinkEntry{
+			Time:        t0.Add(2 * time.Second),
+			Level:       slog.LevelWarn,
+			Message:     "reconnecting",
+			LoggerNames: []string{"conn"},
+			Fields: slog.M(
+				slog.F("attempt", "x"),
+				slog.F("repeated", 2),
+				slog.F("first_seen", t0),
+				slog.F("last_seen", t0.Add(2*time.Second)),
+			),
+		}, s.Entries[1])
+		assert.Equal(t, "fields", slog.M(slog.F("attempt", "y")), s.Entries[2].Fields)
+		assert.Equal(t, "fields", slog.M(slog.F("attempt", "x")), s.Entries[3].Fields)
+
+		ds.Sync()
+		assert.Len(t, "entries", 4, s.Entries)
+		assert.Equal(t, "syncs", 1, s.Syncs)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		ds := slogdedup.Sink(s, slogdedup.Options{Window: 5 * time.Second})
+		ds.LogEntry(bg, entry(0, "a"))
+		ds.LogEntry(bg, entry(1, "b"))
+		ds.LogEntry(bg, entry(2, "a"))
+		ds.LogEntry(bg, entry(3, "b"))
+		ds.LogEntry(bg, entry(6, "a"))
+		ds.LogEntry(bg, entry(20, "c"))
+
+		assert.Len(t, "entries", 5, s.Entries)
+		assert.Equal(t, "msg", "a", s.Entries[0].Message)
+		assert.Equal(t, "msg", "b", s.Entries[1].Message)
+		assert.Equal(t, "msg", "b", s.Entries[2].Message)
+		assert.Equal(t, "repeated", slog.F("repeated", 1), s.Entries[2].Fields[0])
+		assert.Equal(t, "msg", "a", s.Entries[3].Message)
+		assert.Equal(t, "repeated", slog.F("repeated", 2), s.Entries[3].Fields[0])
+		assert.Equal(t, "msg", "c", s.Entries[4].Message)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		t.Parallel()
+
+		now := t0
+		s := &sinktest.Sink{}
+		ds := slogdedup.SinkWithClock(s, slogdedup.Options{Window: 5 * time.Second}, func() time.Time {
+			return now
+		}, func(time.Duration, func()) {})
+		ds.LogEntry(bg, entry(0, "a"))
+		ds.LogEntry(bg, entry(1, "a"))
+		now = t0.Add(2 * time.Second)
+		ds.Sync()
+		assert.Len(t, "entries", 1, s.Entries)
+
+		ds.LogEntry(bg, entry(4, "a"))
+		now = t0.Add(5 * time.Second)
+		ds.Sync()
+		assert.Len(t, "entries", 2, s.Entries)
+		assert.Equal(t, "repeated", slog.F("repeated", 2), s.Entries[1].Fields[0])
+
+		// The burst goes on after Sync.
+		ds.LogEntry(bg, entry(6, "a"))
+		now = t0.Add(20 * time.Second)
+		ds.Sync()
+		assert.Equal(t, "messages", []string{"a", "a", "a"}, s.Messages())
+		assert.Equal(t, "repeated", slog.F("repeated", 1), s.Entries[2].Fields[0])
+		assert.Equal(t, "syncs", 3, s.Syncs)
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		t.Parallel()
+
+		now := t0
+		var timers []time.Duration
+		var fire func()
+		s := &sinktest.Sink{}
+		ds := slogdedup.SinkWithClock(s, slogdedup.Options{Window: 5 * time.Second}, func() time.Time {
+			return now
+		}, func(d time.Duration, fn func()) {
+			timers = append(timers, d)
+			fire = fn
+		})
+		ds.LogEntry(bg, entry(0, "a"))
+		ds.LogEntry(bg, entry(1, "a"))
+		ds.LogEntry(bg, entry(2, "a"))
+		assert.Equal(t, "timers", []time.Duration{5 * time.Second}, timers)
+
+		now = t0.Add(5 * time.Second)
+		fire()
+		assert.Len(t, "entries", 1, s.Entries)
+		assert.Equal(t, "timers", []time.Duration{5 * time.Second, 2 * time.Second}, timers)
+
+		now = t0.Add(7 * time.Second)
+		fire()
+		assert.Len(t, "entries", 2, s.Entries)
+		assert.Equal(t, "repeated", slog.F("repeated", 2), s.Entries[1].Fields[0])
+		assert.Len(t, "timers", 2, timers)
+	})
+
+	t.Run("errorLevel", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		l := slog.Make(slogdedup.Sink(s, slogdedup.Options{Window: time.Minute}))
+		for i := 0; i < 5; i++ {
+			// A fresh error is wrapped on every attempt.
+			l.Error(bg, "dial failed", slog.Error(fmt.Errorf("dial: %w", errors.New("refused"))))
+		}
+		assert.Len(t, "entries", 1, s.Entries)
+		assert.Equal(t, "syncs", 5, s.Syncs)
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		t.Parallel()
+
+		type addr struct {
+			Host string
+			Port *int
+		}
+		port := func(p int) *int {
+			return &p
+		}
+
+		s := &sinktest.Sink{}
+		ds := slogdedup.Sink(s, slogdedup.Options{})
+		ds.LogEntry(bg, entry(0, "a", slog.F("addr", &addr{Host: "db", Port: port(5432)})))
+		ds.LogEntry(bg, entry(1, "a", slog.F("addr", &addr{Host: "db", Port: port(5432)})))
+		ds.LogEntry(bg, entry(2, "a", slog.F("addr", &addr{Host: "db", Port: port(5433)})))
+
+		assert.Len(t, "entries", 3, s.Entries)
+		assert.Equal(t, "repeated", slog.F("repeated", 1), s.Entries[1].Fields[1])
+	})
+
+	t.Run("values", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		lazy := slog.LogValuerFunc(func() interface{} {
+			calls++
+			return "v"
+		})
+
+		s := &sinktest.Sink{}
+		ds := slogdedup.Sink(s, slogdedup.Options{})
+		ds.LogEntry(bg, entry(0, "a", slog.Group("g", slog.F("n", 1)), slog.F("lazy", lazy)))
+		ds.LogEntry(bg, entry(1, "a", slog.Group("g", slog.F("n", 1)), slog.F("lazy", lazy)))
+		ds.LogEntry(bg, entry(2, "a", slog.Group("g", slog.F("n", 2)), slog.F("lazy", lazy)))
+
+		assert.Len(t, "entries", 3, s.Entries)
+		assert.Equal(t, "repeated", slog.F("repeated", 1), s.Entries[1].Fields[2])
+		assert.Equal(t, "calls", 0, calls)
+	})
+}
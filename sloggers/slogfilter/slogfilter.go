@@ -0,0 +1,223 @@
+// Package slogfilter contains a slog.Sink that forwards entries
+// to another sink only if they match a set of rules.
+//
+// For example, to keep health check entries out of stackdriver
+// while still writing them to stdout:
+//
+//	l := slog.Make(
+//		sloghuman.Sink(os.Stdout),
+//		slogfilter.Sink(slogstackdriver.Sink(os.Stderr), &slogfilter.Options{
+//			Deny: []slogfilter.Rule{
+//				slogfilter.All(
+//					slogfilter.FieldEquals("path", "/healthz"),
+//					slogfilter.MaxLevel(slog.LevelInfo),
+//				),
+//			},
+//		}),
+//	)
+package slogfilter // import "cdr.dev/slog/v3/sloggers/slogfilter"
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"cdr.dev/slog/v3"
+)
+
+// Options configures the filter.
+type Options struct {
+	// Allow lists the rules of which an entry must match at
+	// least one to be forwarded. If empty, all entries are
+	// allowed.
+	Allow []Rule
+
+	// Deny lists the rules of which an entry must match none
+	// to be forwarded. Deny takes precedence over Allow.
+	Deny []Rule
+}
+
+// Sink returns a slog.Sink that forwards the entries matching
+// opts to s.
+//
+// opts may be nil in which case every entry is forwarded.
+func Sink(s slog.Sink, opts *Options) slog.Sink {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return filterSink{
+		s:     s,
+		allow: append([]Rule(nil), opts.Allow...),
+		deny:  append([]Rule(nil), opts.Deny...),
+	}
+}
+
+type filterSink struct {
+	s     slog.Sink
+	allow []Rule
+	deny  []Rule
+}
+
+var _ slog.LevelEnabler = filterSink{}
+
+func (s filterSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	if !s.matches(ent) {
+		return
+	}
+	s.s.LogEntry(ctx, ent)
+}
+
+func (s filterSink) matches(ent slog.SinkEntry) bool {
+	for _, r := range s.deny {
+		if r(ent) {
+			return false
+		}
+	}
+	if len(s.allow) == 0 {
+		return true
+	}
+	for _, r := range s.allow {
+		if r(ent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s filterSink) Enabled(ctx context.Context, level slog.Level) bool {
+	le, ok := s.s.(slog.LevelEnabler)
+	return !ok || le.Enabled(ctx, level)
+}
+
+func (s filterSink) Sync() {
+	s.s.Sync()
+}
+
+// Rule reports whether an entry matches.
+type Rule func(ent slog.SinkEntry) bool
+
+// MinLevel matches entries at or above level.
+func MinLevel(level slog.Level) Rule {
+	return func(ent slog.SinkEntry) bool {
+		return ent.Level >= level
+	}
+}
+
+// MaxLevel matches entries at or below level.
+func MaxLevel(level slog.Level) Rule {
+	return func(ent slog.SinkEntry) bool {
+		return ent.Level <= level
+	}
+}
+
+// Named matches entries whose logger names start with the
+// names in prefix. The names are joined with "." so
+// "http.client" matches the names "http", "client" and
+// "http", "client", "retry" but not "http" or "httpx".
+func Named(prefix string) Rule {
+	segs := strings.Split(prefix, ".")
+	return func(ent slog.SinkEntry) bool {
+		i := 0
+		for _, name := range ent.LoggerNames {
+			for _, seg := range strings.Split(name, ".") {
+				if i == len(segs) {
+					return true
+				}
+				if segs[i] != seg {
+					return false
+				}
+				i++
+			}
+		}
+		return i == len(segs)
+	}
+}
+
+// Message matches entries whose message matches re.
+func Message(re *regexp.Regexp) Rule {
+	return func(ent slog.SinkEntry) bool {
+		return re.MatchString(ent.Message)
+	}
+}
+
+// HasField matches entries with a field called name.
+//
+// Fields in groups are matched with their path joined
+// by "." such as "db.query".
+func HasField(name string) Rule {
+	return func(ent slog.SinkEntry) bool {
+		_, ok := lookupField(ent.Fields, name)
+		return ok
+	}
+}
+
+// FieldEquals matches entries with a field called name whose
+// value is deeply equal to value after resolving any
+// slog.LogValuer. Note that the types must match so the
+// int 1 does not equal the int64 1.
+//
+// Fields in groups are matched with their path joined
+// by "." such as "db.query".
+func FieldEquals(name string, value interface{}) Rule {
+	return func(ent slog.SinkEntry) bool {
+		v, ok := lookupField(ent.Fields, name)
+		return ok && reflect.DeepEqual(v, value)
+	}
+}
+
+// All matches entries matching all of the rules.
+func All(rules ...Rule) Rule {
+	return func(ent slog.SinkEntry) bool {
+		for _, r := range rules {
+			if !r(ent) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any matches entries matching any of the rules.
+func Any(rules ...Rule) Rule {
+	return func(ent slog.SinkEntry) bool {
+		for _, r := range rules {
+			if r(ent) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches entries not matching r.
+func Not(r Rule) Rule {
+	return func(ent slog.SinkEntry) bool {
+		return !r(ent)
+	}
+}
+
+// lookupField returns the resolved value of the last field
+// at path in m.
+func lookupField(m slog.Map, path string) (interface{}, bool) {
+	var (
+		v  interface{}
+		ok bool
+	)
+	for _, f := range m {
+		if f.Name == path {
+			v, ok = slog.ResolveValue(f.Value), true
+			continue
+		}
+		rest, found := strings.CutPrefix(path, f.Name+".")
+		if !found {
+			continue
+		}
+		if gm, isMap := slog.ResolveValue(f.Value).(slog.Map); isMap {
+			if gv, gok := lookupField(gm, rest); gok {
+				v, ok = gv, true
+			}
+		}
+	}
+	return v, ok
+}
@@ -0,0 +1,86 @@
+package slogfilter_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/internal/sinktest"
+	"cdr.dev/slog/v3/sloggers/slogfilter"
+)
+
+var bg = context.Background()
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		l := slog.Make(slogfilter.Sink(s, nil))
+		l.Info(bg, "hi")
+		l.Error(bg, "hi")
+
+		assert.Len(t, "entries", 2, s.Entries)
+		assert.Equal(t, "syncs", 1, s.Syncs)
+	})
+
+	t.Run("allowDeny", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		l := slog.Make(slogfilter.Sink(s, &slogfilter.Options{
+			Allow: []slogfilter.Rule{
+				slogfilter.Named("http"),
+				slogfilter.MinLevel(slog.LevelError),
+			},
+			Deny: []slogfilter.Rule{
+				slogfilter.All(
+					slogfilter.FieldEquals("req.path", "/healthz"),
+					slogfilter.MaxLevel(slog.LevelInfo),
+				),
+				slogfilter.Message(regexp.MustCompile(`^noisy`)),
+			},
+		}))
+
+		http := l.Named("http").WithGroup("req").With(slog.F("path", "/healthz"))
+		http.Info(bg, "health check")
+		http.Warn(bg, "slow health check")
+		http.Info(bg, "noisy")
+		l.Named("http").Named("client").Info(bg, "request", slog.F("path", "/healthz"))
+		l.Named("httpx").Info(bg, "dropped")
+		l.Named("db").Error(bg, "failed")
+
+		assert.Len(t, "entries", 3, s.Entries)
+		assert.Equal(t, "msg", "slow health check", s.Entries[0].Message)
+		assert.Equal(t, "msg", "request", s.Entries[1].Message)
+		assert.Equal(t, "msg", "failed", s.Entries[2].Message)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		t.Parallel()
+
+		ent := slog.SinkEntry{
+			Message: "hi",
+			Fields: slog.M(
+				slog.F("a", 1),
+				slog.Group("db", slog.F("query", "select 1")),
+				slog.F("lazy", slog.LogValuerFunc(func() interface{} {
+					return "value"
+				})),
+			),
+		}
+		assert.True(t, "has a", slogfilter.HasField("a")(ent))
+		assert.False(t, "has b", slogfilter.HasField("b")(ent))
+		assert.True(t, "has db.query", slogfilter.HasField("db.query")(ent))
+		assert.True(t, "a equals", slogfilter.FieldEquals("a", 1)(ent))
+		assert.False(t, "a equals int64", slogfilter.FieldEquals("a", int64(1))(ent))
+		assert.True(t, "db.query equals", slogfilter.FieldEquals("db.query", "select 1")(ent))
+		assert.True(t, "lazy equals", slogfilter.FieldEquals("lazy", "value")(ent))
+		assert.True(t, "any", slogfilter.Any(slogfilter.HasField("b"), slogfilter.HasField("a"))(ent))
+		assert.True(t, "not", slogfilter.Not(slogfilter.HasField("b"))(ent))
+	})
+}
@@ -0,0 +1,104 @@
+//go:build go1.21
+
+// Package sloghandler contains the slogger that forwards
+// entries to a log/slog Handler.
+package sloghandler // import "cdr.dev/slog/v3/sloggers/sloghandler"
+
+import (
+	"context"
+	stdslog "log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"cdr.dev/slog/v3"
+)
+
+// Sink creates a slog.Sink that converts every entry
+// into a log/slog Record and passes it to h.
+//
+// Fields with a slog.Map value become groups and the fields of
+// slog.LogFielder errors are added as a group named after the
+// error field with a "_fields" suffix such as "error_fields".
+// The logger names, source location and span context
+// are added as the "logger_names", "source", "trace" and
+// "span" attributes. The span context is also stored in
+// the context passed to h.
+func Sink(h stdslog.Handler) slog.Sink {
+	return handlerSink{
+		h: h,
+	}
+}
+
+type handlerSink struct {
+	h stdslog.Handler
+}
+
+func (s handlerSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	if ent.SpanContext.IsValid() && !trace.SpanContextFromContext(ctx).Equal(ent.SpanContext) {
+		ctx = trace.ContextWithSpanContext(ctx, ent.SpanContext)
+	}
+
+	level := stdlibLevel(ent.Level)
+	if !s.h.Enabled(ctx, level) {
+		return
+	}
+
+	r := stdslog.NewRecord(ent.Time, level, ent.Message, 0)
+
+	if len(ent.LoggerNames) > 0 {
+		r.AddAttrs(stdslog.Any("logger_names", ent.LoggerNames))
+	}
+
+	if ent.File != "" {
+		r.AddAttrs(stdslog.Any(stdslog.SourceKey, &stdslog.Source{
+			Function: ent.Func,
+			File:     ent.File,
+			Line:     ent.Line,
+		}))
+	}
+
+	if ent.SpanContext.IsValid() {
+		r.AddAttrs(
+			stdslog.String("trace", ent.SpanContext.TraceID().String()),
+			stdslog.String("span", ent.SpanContext.SpanID().String()),
+		)
+	}
+
+	r.AddAttrs(attrs(slog.RedactMap(ent.Fields))...)
+
+	_ = s.h.Handle(ctx, r)
+}
+
+func (s handlerSink) Sync() {}
+
+func attrs(m slog.Map) []stdslog.Attr {
+	as := make([]stdslog.Attr, 0, len(m))
+	for _, f := range m {
+		v := slog.ResolveValue(f.Value)
+		if fm, ok := v.(slog.Map); ok {
+			as = append(as, stdslog.Attr{
+				Key:   f.Name,
+				Value: stdslog.GroupValue(attrs(fm)...),
+			})
+			continue
+		}
+		as = append(as, stdslog.Any(f.Name, v))
+		if err, ok := v.(error); ok {
+			if efs := slog.ErrorFields(err); len(efs) > 0 {
+				// The error keeps its value for ReplaceAttr
+				// so its fields are a sibling group.
+				as = append(as, stdslog.Attr{
+					Key:   f.Name + "_fields",
+					Value: stdslog.GroupValue(attrs(slog.RedactMap(efs))...),
+				})
+			}
+		}
+	}
+	return as
+}
+
+// stdlibLevel maps level onto log/slog's scale where
+// levels are 4 apart instead of 10.
+func stdlibLevel(level slog.Level) stdslog.Level {
+	return stdslog.Level((level - slog.LevelInfo) * 4 / (slog.LevelWarn - slog.LevelInfo))
+}
@@ -0,0 +1,79 @@
+//go:build go1.21
+
+package sloghandler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	stdslog "log/slog"
+	"runtime"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/sloggers/sloghandler"
+)
+
+var _, sloghandlerTestFile, _, _ = runtime.Caller(0)
+
+var bg = context.Background()
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	tp := sdktrace.NewTracerProvider()
+	tracer := tp.Tracer("tracer")
+	ctx, span := tracer.Start(bg, "trace")
+	span.End()
+	_ = tp.Shutdown(bg)
+
+	b := &bytes.Buffer{}
+	h := stdslog.NewJSONHandler(b, &stdslog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a stdslog.Attr) stdslog.Attr {
+			if len(groups) == 0 && a.Key == stdslog.TimeKey {
+				return stdslog.Attr{}
+			}
+			return a
+		},
+	})
+	l := slog.Make(sloghandler.Sink(h))
+	l = l.Named("named")
+	l.Debug(ctx, "dropped")
+	l.Critical(ctx, "line1\n\nline2", slog.F("wowow", "me\nyou"), slog.F("db", slog.M(
+		slog.F("rows", 3),
+	)))
+
+	exp := fmt.Sprintf(`{"level":"ERROR+4","msg":"line1\n\nline2","logger_names":["named"],"source":{"function":"cdr.dev/slog/v3/sloggers/sloghandler_test.TestSink","file":"%v","line":45},"trace":"%v","span":"%v","wowow":"me\nyou","db":{"rows":3}}
+`, sloghandlerTestFile, span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	assert.Equal(t, "entry", exp, b.String())
+}
+
+type fieldsError struct{}
+
+func (fieldsError) Error() string {
+	return "user not found"
+}
+
+func (fieldsError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("user_id", 42)}
+}
+
+func TestErrorFields(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	h := stdslog.NewTextHandler(b, &stdslog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a stdslog.Attr) stdslog.Attr {
+			if len(groups) == 0 && (a.Key == stdslog.TimeKey || a.Key == stdslog.SourceKey) {
+				return stdslog.Attr{}
+			}
+			return a
+		},
+	})
+	slog.Make(sloghandler.Sink(h)).Info(bg, "get user failed", slog.Error(fmt.Errorf("get user: %w", fieldsError{})))
+
+	assert.Equal(t, "entry", "level=INFO msg=\"get user failed\" error=\"get user: user not found\" error_fields.user_id=42\n", b.String())
+}
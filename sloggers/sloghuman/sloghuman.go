@@ -6,13 +6,20 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	"sync"
 
 	"cdr.dev/slog/v3"
 	"cdr.dev/slog/v3/internal/entryhuman"
+	"cdr.dev/slog/v3/internal/fallback"
 	"cdr.dev/slog/v3/internal/syncwriter"
 )
 
+// Make the default Logger write to stderr.
+func init() {
+	fallback.SetSink(Sink(os.Stderr))
+}
+
 // Sink creates a slog.Sink that writes logs in a human
 // readable YAML like format to the given writer.
 //
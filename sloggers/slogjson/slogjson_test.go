@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -35,7 +36,7 @@ func TestMake(t *testing.T) {
 	l.Error(ctx, "line1\n\nline2", slog.F("wowow", "me\nyou"))
 
 	j := entryjson.Filter(b.String(), "ts")
-	exp := fmt.Sprintf(`{"level":"ERROR","msg":"line1\n\nline2","caller":"%v:35","func":"cdr.dev/slog/v3/sloggers/slogjson_test.TestMake","logger_names":["named"],"trace":"%v","span":"%v","fields":{"wowow":"me\nyou"}}
+	exp := fmt.Sprintf(`{"level":"ERROR","msg":"line1\n\nline2","caller":"%v:36","func":"cdr.dev/slog/v3/sloggers/slogjson_test.TestMake","logger_names":["named"],"trace":"%v","span":"%v","fields":{"wowow":"me\nyou"}}
 `, slogjsonTestFile, span.SpanContext().TraceID().String(), span.SpanContext().SpanID().String())
 	assert.Equal(t, "entry", exp, j)
 }
@@ -61,7 +62,20 @@ func TestNoDriverValue(t *testing.T) {
 	l.Error(bg, "error!", slog.F("inval", invalidField), slog.F("val", validField), slog.F("int", validInt))
 
 	j := entryjson.Filter(b.String(), "ts")
-	exp := fmt.Sprintf(`{"level":"ERROR","msg":"error!","caller":"%v:61","func":"cdr.dev/slog/v3/sloggers/slogjson_test.TestNoDriverValue","logger_names":["named"],"fields":{"inval":null,"val":"cat","int":42}}
+	exp := fmt.Sprintf(`{"level":"ERROR","msg":"error!","caller":"%v:62","func":"cdr.dev/slog/v3/sloggers/slogjson_test.TestNoDriverValue","logger_names":["named"],"fields":{"inval":null,"val":"cat","int":42}}
+`, slogjsonTestFile)
+	assert.Equal(t, "entry", exp, j)
+}
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	l := slog.Make(slogjson.Sink(b)).WithGroup("db").With(slog.F("name", "pg"))
+	l.Info(bg, "query", slog.Group("query", slog.F("rows", 3)))
+
+	j := entryjson.Filter(b.String(), "ts")
+	exp := fmt.Sprintf(`{"level":"INFO","msg":"query","caller":"%v:75","func":"cdr.dev/slog/v3/sloggers/slogjson_test.TestGroups","fields":{"db":{"name":"pg","query":{"rows":3}}}}
 `, slogjsonTestFile)
 	assert.Equal(t, "entry", exp, j)
 }
@@ -99,6 +113,41 @@ func TestContextErrors(t *testing.T) {
 		l.Error(bg, "request failed", slog.Error(fmt.Errorf("dial: %w", context.DeadlineExceeded)))
 
 		j := entryjson.Filter(b.String(), "ts")
-		assert.True(t, "error contains dial: context deadline exceeded", strings.Contains(j, `"error":"dial: context deadline exceeded"`))
+		assert.True(t, "error contains dial: context deadline exceeded", strings.Contains(j, `"error":[{"msg":"dial","type":"*fmt.wrapError"},{"msg":"context deadline exceeded","type":"context.deadlineExceededError"}]`))
+	})
+
+	t.Run("Joined", func(t *testing.T) {
+		t.Parallel()
+
+		b := &bytes.Buffer{}
+		l := slog.Make(slogjson.Sink(b))
+		l.Error(bg, "shutdown failed", slog.Error(errors.Join(
+			fmt.Errorf("close db: %w", context.Canceled),
+			errors.New("flush metrics"),
+		)))
+
+		j := entryjson.Filter(b.String(), "ts")
+		assert.True(t, "error contains joined errors", strings.Contains(j, `"error":[{"type":"*errors.joinError","errors":[[{"msg":"close db","type":"*fmt.wrapError"},{"msg":"context canceled","type":"*errors.errorString"}],[{"msg":"flush metrics","type":"*errors.errorString"}]]}]`))
 	})
+
+	t.Run("Fields", func(t *testing.T) {
+		t.Parallel()
+
+		b := &bytes.Buffer{}
+		l := slog.Make(slogjson.Sink(b))
+		l.Error(bg, "get user failed", slog.Error(fmt.Errorf("get user: %w", fieldsError{})))
+
+		j := entryjson.Filter(b.String(), "ts")
+		assert.True(t, "error contains fields", strings.Contains(j, `"error":[{"msg":"get user","type":"*fmt.wrapError"},{"msg":"user not found","type":"slogjson_test.fieldsError","fields":{"user_id":42}}]`))
+	})
+}
+
+type fieldsError struct{}
+
+func (fieldsError) Error() string {
+	return "user not found"
+}
+
+func (fieldsError) LogFields() []slog.Field {
+	return []slog.Field{slog.F("user_id", 42)}
 }
@@ -0,0 +1,11 @@
+package slogsample
+
+import (
+	"time"
+
+	"cdr.dev/slog/v3"
+)
+
+func SinkWithClock(s slog.Sink, opts Options, now func() time.Time, afterFunc func(time.Duration, func())) slog.Sink {
+	return makeSink(s, opts, now, afterFunc)
+}
@@ -0,0 +1,225 @@
+// Package slogsample contains a slog.Sink that samples
+// high volume entries before forwarding them to another sink.
+//
+// Entries are counted per level and message. Within each
+// interval, the first entries of a key are forwarded and
+// after that only every Mth one. The number of dropped entries
+// is reported with a summary entry per key once the interval
+// is over, even if nothing is logged after it.
+package slogsample // import "cdr.dev/slog/v3/sloggers/slogsample"
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"cdr.dev/slog/v3"
+)
+
+// Options configures the sampling.
+type Options struct {
+	// Interval is the duration after which the counters are
+	// reset. It defaults to one second.
+	Interval time.Duration
+
+	// First is the number of entries per key forwarded in
+	// every interval before sampling starts.
+	//
+	// If both First and Thereafter are zero, they default
+	// to 100.
+	First int
+
+	// Thereafter makes every Thereafter'th entry per key
+	// forwarded once First has been reached. If zero, all
+	// further entries of the interval are dropped.
+	Thereafter int
+
+	// ExemptLevel is the level at and above which entries
+	// are always forwarded. If nil, it is slog.LevelError.
+	// Point it to a level above slog.LevelFatal to sample
+	// every level.
+	ExemptLevel *slog.Level
+}
+
+// Sink returns a slog.Sink that samples entries before
+// forwarding them to s.
+//
+// The summary of the dropped entries of an interval is written
+// when the interval is over by a timer or by the first entry
+// logged after it, or before that by Sync. Summaries have the
+// level and message of the dropped entries in the sampled_level
+// and sampled_msg fields and their count in the dropped field.
+func Sink(s slog.Sink, opts Options) slog.Sink {
+	return makeSink(s, opts, time.Now, func(d time.Duration, fn func()) {
+		time.AfterFunc(d, fn)
+	})
+}
+
+func makeSink(s slog.Sink, opts Options, now func() time.Time, afterFunc func(time.Duration, func())) *sampleSink {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.First == 0 && opts.Thereafter == 0 {
+		opts.First = 100
+		opts.Thereafter = 100
+	}
+	exempt := slog.LevelError
+	if opts.ExemptLevel != nil {
+		exempt = *opts.ExemptLevel
+	}
+	return &sampleSink{
+		s:         s,
+		opts:      opts,
+		exempt:    exempt,
+		now:       now,
+		afterFunc: afterFunc,
+		counts:    make(map[key]*count),
+	}
+}
+
+type key struct {
+	level slog.Level
+	msg   string
+}
+
+type count struct {
+	n       int
+	dropped int
+}
+
+type sampleSink struct {
+	s    slog.Sink
+	opts Options
+	// exempt is the level of Options.ExemptLevel.
+	exempt    slog.Level
+	now       func() time.Time
+	afterFunc func(time.Duration, func())
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[key]*count
+	// flushStart is the start of the interval whose end
+	// the timer flushing the summaries is set for.
+	flushStart time.Time
+}
+
+var _ slog.LevelEnabler = &sampleSink{}
+
+func (s *sampleSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	if ent.Level >= s.exempt {
+		s.s.LogEntry(ctx, ent)
+		return
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	var summaries []slog.SinkEntry
+	if now.Sub(s.start) >= s.opts.Interval {
+		summaries = s.summaries(now)
+		s.counts = make(map[key]*count, len(s.counts))
+		s.start = now
+	}
+
+	k := key{level: ent.Level, msg: ent.Message}
+	c, ok := s.counts[k]
+	if !ok {
+		c = &count{}
+		s.counts[k] = c
+	}
+	c.n++
+	forward := c.n <= s.opts.First ||
+		(s.opts.Thereafter > 0 && (c.n-s.opts.First)%s.opts.Thereafter == 0)
+	if !forward {
+		c.dropped++
+		if !s.flushStart.Equal(s.start) {
+			// Report the drops even if nothing is
+			// logged once the interval is over.
+			s.flushStart = s.start
+			start := s.start
+			s.afterFunc(start.Add(s.opts.Interval).Sub(now), func() {
+				s.flush(start)
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		s.s.LogEntry(ctx, sum)
+	}
+	if forward {
+		s.s.LogEntry(ctx, ent)
+	}
+}
+
+// flush ends the interval that began at start if it is still
+// the current one and writes its summaries.
+func (s *sampleSink) flush(start time.Time) {
+	s.mu.Lock()
+	if !s.start.Equal(start) {
+		// An entry already ended the interval.
+		s.mu.Unlock()
+		return
+	}
+	now := s.now()
+	summaries := s.summaries(now)
+	s.counts = make(map[key]*count, len(s.counts))
+	s.start = now
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		s.s.LogEntry(context.Background(), sum)
+	}
+}
+
+// summaries returns the summary entries of the dropped entries
+// and resets the dropped counters. s.mu must be held.
+func (s *sampleSink) summaries(now time.Time) []slog.SinkEntry {
+	var keys []key
+	for k, c := range s.counts {
+		if c.dropped > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+
+	ents := make([]slog.SinkEntry, 0, len(keys))
+	for _, k := range keys {
+		c := s.counts[k]
+		ents = append(ents, slog.SinkEntry{
+			Time:    now.UTC(),
+			Level:   k.level,
+			Message: "dropped sampled entries",
+			Fields: slog.M(
+				slog.F("sampled_level", k.level),
+				slog.F("sampled_msg", k.msg),
+				slog.F("dropped", c.dropped),
+			),
+		})
+		c.dropped = 0
+	}
+	return ents
+}
+
+func (s *sampleSink) Enabled(ctx context.Context, level slog.Level) bool {
+	le, ok := s.s.(slog.LevelEnabler)
+	return !ok || le.Enabled(ctx, level)
+}
+
+// Sync writes the summaries of the entries dropped so
+// far and syncs the underlying sink.
+func (s *sampleSink) Sync() {
+	s.mu.Lock()
+	summaries := s.summaries(s.now())
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		s.s.LogEntry(context.Background(), sum)
+	}
+	s.s.Sync()
+}
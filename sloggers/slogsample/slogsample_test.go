@@ -0,0 +1,160 @@
+package slogsample_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+	"cdr.dev/slog/v3/internal/sinktest"
+	"cdr.dev/slog/v3/sloggers/slogsample"
+)
+
+var bg = context.Background()
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sample", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2000, time.February, 5, 4, 4, 4, 0, time.UTC)
+		s := &sinktest.Sink{}
+		l := slog.Make(slogsample.SinkWithClock(s, slogsample.Options{
+			Interval:   time.Second,
+			First:      2,
+			Thereafter: 3,
+		}, func() time.Time {
+			return now
+		}, func(time.Duration, func()) {}))
+
+		for i := 0; i < 9; i++ {
+			l.Info(bg, "hot")
+		}
+		l.Info(bg, "cold")
+		// Log does not Sync like Error.
+		l.Log(bg, slog.SinkEntry{Level: slog.LevelError, Message: "hot"})
+		l.Log(bg, slog.SinkEntry{Level: slog.LevelError, Message: "hot"})
+
+		assert.Equal(t, "messages", []string{"hot", "hot", "hot", "hot", "cold", "hot", "hot"}, s.Messages())
+
+		now = now.Add(time.Second)
+		s.Entries = nil
+		l.Info(bg, "hot")
+
+		assert.Len(t, "entries", 2, s.Entries)
+		sum := s.Entries[0]
+		assert.Equal(t, "msg", "dropped sampled entries", sum.Message)
+		assert.Equal(t, "level", slog.LevelInfo, sum.Level)
+		assert.Equal(t, "fields", slog.M(
+			slog.F("sampled_level", slog.LevelInfo),
+			slog.F("sampled_msg", "hot"),
+			slog.F("dropped", 5),
+		), sum.Fields)
+		assert.Equal(t, "msg", "hot", s.Entries[1].Message)
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2000, time.February, 5, 4, 4, 4, 0, time.UTC)
+		var timers []time.Duration
+		var flush func()
+		s := &sinktest.Sink{}
+		l := slog.Make(slogsample.SinkWithClock(s, slogsample.Options{
+			Interval: time.Second,
+			First:    1,
+		}, func() time.Time {
+			return now
+		}, func(d time.Duration, fn func()) {
+			timers = append(timers, d)
+			flush = fn
+		}))
+
+		l.Info(bg, "hot")
+		now = now.Add(100 * time.Millisecond)
+		l.Info(bg, "hot")
+		l.Info(bg, "hot")
+		assert.Equal(t, "timers", []time.Duration{900 * time.Millisecond}, timers)
+		assert.Equal(t, "messages", []string{"hot"}, s.Messages())
+
+		// The hot loop stopped, the timer reports the drops.
+		now = now.Add(900 * time.Millisecond)
+		flush()
+		assert.Equal(t, "messages", []string{"hot", "dropped sampled entries"}, s.Messages())
+		assert.Equal(t, "dropped", slog.F("dropped", 2), s.Entries[1].Fields[2])
+
+		// A stale timer does nothing.
+		flush()
+		assert.Len(t, "entries", 2, s.Entries)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		l := slog.Make(slogsample.Sink(s, slogsample.Options{Interval: time.Hour}))
+		for i := 0; i < 300; i++ {
+			l.Info(bg, "hot")
+		}
+		assert.Len(t, "entries", 102, s.Entries)
+	})
+
+	t.Run("exemptDebug", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		l := slog.Make(slogsample.Sink(s, slogsample.Options{
+			Interval:    time.Hour,
+			First:       1,
+			ExemptLevel: levelPtr(slog.LevelDebug),
+		})).Leveled(slog.LevelDebug)
+		l.Debug(bg, "a")
+		l.Debug(bg, "a")
+		l.Info(bg, "a")
+		l.Info(bg, "a")
+
+		assert.Len(t, "entries", 4, s.Entries)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		t.Parallel()
+
+		s := &sinktest.Sink{}
+		ss := slogsample.Sink(s, slogsample.Options{
+			Interval:    time.Hour,
+			First:       1,
+			ExemptLevel: levelPtr(slog.LevelFatal + 1),
+		})
+		l := slog.Make(ss)
+		l.Info(bg, "b")
+		l.Info(bg, "b")
+		l.Warn(bg, "a")
+		l.Warn(bg, "a")
+		l.Warn(bg, "a")
+		l.Info(bg, "a")
+		l.Info(bg, "a")
+
+		assert.Equal(t, "messages", []string{"b", "a", "a"}, s.Messages())
+
+		s.Entries = nil
+		ss.Sync()
+		assert.Equal(t, "syncs", 1, s.Syncs)
+		assert.Len(t, "entries", 3, s.Entries)
+		assert.Equal(t, "sampled_msg", slog.F("sampled_msg", "a"), s.Entries[0].Fields[1])
+		assert.Equal(t, "sampled_msg", slog.F("sampled_msg", "b"), s.Entries[1].Fields[1])
+		assert.Equal(t, "sampled_msg", slog.F("sampled_msg", "a"), s.Entries[2].Fields[1])
+		assert.Equal(t, "level", slog.LevelWarn, s.Entries[2].Level)
+		assert.Equal(t, "dropped", slog.F("dropped", 2), s.Entries[2].Fields[2])
+
+		// Summaries are only written once.
+		s.Entries = nil
+		ss.Sync()
+		assert.Len(t, "entries", 0, s.Entries)
+	})
+}
+
+func levelPtr(l slog.Level) *slog.Level {
+	return &l
+}
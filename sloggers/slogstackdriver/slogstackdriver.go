@@ -82,7 +82,7 @@ func (s stackdriverSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
 		)
 	}
 
-	e = append(e, ent.Fields...)
+	e = appendFields(e, ent)
 
 	buf, _ := json.Marshal(e)
 
@@ -90,6 +90,30 @@ func (s stackdriverSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
 	s.w.Write("slogstackdriver", buf)
 }
 
+// appendFields appends the fields of ent to e.
+//
+// The first slog.Stack field is moved to "stack_trace" in the
+// format of Go's panic output so that Error Reporting picks it up.
+// See https://cloud.google.com/error-reporting/docs/formatting-error-messages
+func appendFields(e slog.Map, ent slog.SinkEntry) slog.Map {
+	hasStack := false
+	for _, f := range slog.RedactMap(ent.Fields) {
+		switch v := slog.ResolveValue(f.Value).(type) {
+		case slog.Stack:
+			if hasStack {
+				break
+			}
+			hasStack = true
+			e = append(e, slog.F("stack_trace", ent.Message+"\n\n"+v.String()))
+			continue
+		case slog.Goroutines:
+			f.Value = v.String()
+		}
+		e = append(e, f)
+	}
+	return e
+}
+
 func (s stackdriverSink) Sync() {
 	s.w.Sync("stackdriverSink")
 }
@@ -104,6 +128,24 @@ func sev(level slog.Level) logpbtype.LogSeverity {
 		return logpbtype.LogSeverity_WARNING
 	case slog.LevelError:
 		return logpbtype.LogSeverity_ERROR
+	}
+
+	if c, ok := slog.LookupLevel(level); ok && c.Severity != "" {
+		if s, ok := logpbtype.LogSeverity_value[strings.ToUpper(c.Severity)]; ok {
+			return logpbtype.LogSeverity(s)
+		}
+	}
+
+	// Use the severity of the closest builtin level below.
+	switch {
+	case level < slog.LevelInfo:
+		return logpbtype.LogSeverity_DEBUG
+	case level < slog.LevelWarn:
+		return logpbtype.LogSeverity_INFO
+	case level < slog.LevelError:
+		return logpbtype.LogSeverity_WARNING
+	case level < slog.LevelCritical:
+		return logpbtype.LogSeverity_ERROR
 	default:
 		return logpbtype.LogSeverity_CRITICAL
 	}
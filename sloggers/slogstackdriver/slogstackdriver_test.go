@@ -3,9 +3,11 @@ package slogstackdriver_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,11 +45,16 @@ func TestStackdriver(t *testing.T) {
 
 	j := entryjson.Filter(b.String(), "timestampSeconds")
 	j = entryjson.Filter(j, "timestampNanos")
-	exp := fmt.Sprintf(`{"logging.googleapis.com/severity":"ERROR","severity":"ERROR","message":"line1\n\nline2","logging.googleapis.com/sourceLocation":{"file":"%v","line":40,"function":"cdr.dev/slog/v3/sloggers/slogstackdriver_test.TestStackdriver"},"logging.googleapis.com/operation":{"producer":"meow"},"logging.googleapis.com/trace":"projects/%v/traces/%v","logging.googleapis.com/spanId":"%v","logging.googleapis.com/trace_sampled":%v,"wowow":"me\nyou"}
+	exp := fmt.Sprintf(`{"logging.googleapis.com/severity":"ERROR","severity":"ERROR","message":"line1\n\nline2","logging.googleapis.com/sourceLocation":{"file":"%v","line":42,"function":"cdr.dev/slog/v3/sloggers/slogstackdriver_test.TestStackdriver"},"logging.googleapis.com/operation":{"producer":"meow"},"logging.googleapis.com/trace":"projects/%v/traces/%v","logging.googleapis.com/spanId":"%v","logging.googleapis.com/trace_sampled":%v,"wowow":"me\nyou"}
 `, slogstackdriverTestFile, projectID, span.SpanContext().TraceID(), span.SpanContext().SpanID(), span.SpanContext().IsSampled())
 	assert.Equal(t, "entry", exp, j)
 }
 
+func init() {
+	slog.RegisterLevel(slog.LevelInfo+5, slog.LevelConfig{Name: "NOTICE", Severity: "notice"})
+	slog.RegisterLevel(slog.LevelDebug-5, slog.LevelConfig{Name: "TRACE"})
+}
+
 func TestSevMapping(t *testing.T) {
 	t.Parallel()
 
@@ -56,6 +63,11 @@ func TestSevMapping(t *testing.T) {
 	assert.Equal(t, "level", logpbtype.LogSeverity_WARNING, slogstackdriver.Sev(slog.LevelWarn))
 	assert.Equal(t, "level", logpbtype.LogSeverity_ERROR, slogstackdriver.Sev(slog.LevelError))
 	assert.Equal(t, "level", logpbtype.LogSeverity_CRITICAL, slogstackdriver.Sev(slog.LevelCritical))
+	assert.Equal(t, "level", logpbtype.LogSeverity_CRITICAL, slogstackdriver.Sev(slog.LevelFatal))
+
+	assert.Equal(t, "level", logpbtype.LogSeverity_NOTICE, slogstackdriver.Sev(slog.LevelInfo+5))
+	assert.Equal(t, "level", logpbtype.LogSeverity_DEBUG, slogstackdriver.Sev(slog.LevelDebug-5))
+	assert.Equal(t, "level", logpbtype.LogSeverity_WARNING, slogstackdriver.Sev(slog.LevelWarn+1))
 }
 
 func TestMain(m *testing.M) {
@@ -77,3 +89,33 @@ func metadataClient(t testing.TB) *metadata.Client {
 	t.Cleanup(httpClient.CloseIdleConnections)
 	return client
 }
+
+func TestStackTrace(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	l := slog.Make(slogstackdriver.Sink(b)).StackTraces(slog.LevelError)
+	l.Error(bg, "boom")
+
+	var ent map[string]interface{}
+	err := json.Unmarshal(b.Bytes(), &ent)
+	assert.Success(t, "unmarshal", err)
+
+	st, ok := ent["stack_trace"].(string)
+	assert.True(t, "stack_trace", ok)
+	assert.True(t, "panic format", strings.HasPrefix(st, "boom\n\ngoroutine "))
+	assert.True(t, "caller", strings.Contains(st, "\ncdr.dev/slog/v3/sloggers/slogstackdriver_test.TestStackTrace(...)\n\t"+slogstackdriverTestFile+":"))
+	_, ok = ent["stack"]
+	assert.False(t, "stack", ok)
+}
+
+func TestRedaction(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	l := slog.Make(slogstackdriver.Sink(b))
+	l.Info(bg, "login", slog.F("password", slog.Redacted("secret")))
+
+	assert.True(t, "redacted", strings.Contains(b.String(), `"password":"[REDACTED]"`))
+	assert.False(t, "secret", strings.Contains(b.String(), "secret"))
+}
@@ -106,10 +106,10 @@ func (ts *testSink) LogEntry(_ context.Context, ent slog.SinkEntry) {
 	}
 	f.Fmt(&sb, os.Stdout, ent)
 
-	switch ent.Level {
-	case slog.LevelDebug, slog.LevelInfo, slog.LevelWarn:
+	switch {
+	case ent.Level < slog.LevelError:
 		ts.tb.Log(sb.String())
-	case slog.LevelError, slog.LevelCritical:
+	case ent.Level < slog.LevelFatal:
 		if ts.shouldIgnoreError(ent) {
 			ts.tb.Log(sb.String())
 		} else {
@@ -119,7 +119,7 @@ func (ts *testSink) LogEntry(_ context.Context, ent slog.SinkEntry) {
 			))
 			ts.tb.Error(sb.String())
 		}
-	case slog.LevelFatal:
+	default:
 		sb.WriteString("\n *** slogtest: FATAL log detected; TEST FAILURE ***")
 		ts.tb.Fatal(sb.String())
 	}
@@ -13,6 +13,12 @@ import (
 	"cdr.dev/slog/v3/sloggers/slogtest"
 )
 
+const levelNotice = slog.LevelInfo + 5
+
+func init() {
+	slog.RegisterLevel(levelNotice, slog.LevelConfig{Name: "NOTICE"})
+}
+
 func TestStateless(t *testing.T) {
 	t.Parallel()
 
@@ -176,6 +182,17 @@ func TestSkipCleanup(t *testing.T) {
 	assert.Len(t, "no cleanups", 0, tb.cleanups)
 }
 
+func TestCustomLevels(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+	l := slogtest.Make(tb, &slogtest.Options{SkipCleanup: true})
+	l.Log(bg, slog.SinkEntry{Level: levelNotice})
+	l.Log(bg, slog.SinkEntry{Level: slog.LevelError + 5})
+	assert.Equal(t, "logs", 1, tb.logs)
+	assert.Equal(t, "errors", 1, tb.errors)
+}
+
 func TestUnmarshalable(t *testing.T) {
 	t.Parallel()
 	tb := &fakeTB{}
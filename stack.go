@@ -0,0 +1,262 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackTraces returns a Logger that adds the stack of the
+// logging goroutine as the "stack" field to entries logged
+// at or above level.
+func (l Logger) StackTraces(level Level) Logger {
+	l.stacks.enabled = true
+	l.stacks.level = level
+	return l
+}
+
+// AllStackTraces returns a Logger that adds the stacks of all
+// goroutines as the "goroutines" field to entries logged at or
+// above level. It is expensive and meant for LevelFatal.
+func (l Logger) AllStackTraces(level Level) Logger {
+	l.stacks.allEnabled = true
+	l.stacks.allLevel = level
+	return l
+}
+
+type stackTraces struct {
+	enabled bool
+	level   Level
+
+	allEnabled bool
+	allLevel   Level
+}
+
+// fields returns the stack trace fields for an entry at level.
+// If from is set, the frames above the first one of the function
+// from are trimmed such as those of log/slog.
+func (st stackTraces) fields(level Level, skip int, from string) Map {
+	var m Map
+	if st.enabled && level >= st.level {
+		s := captureStack(skip + 1)
+		if from != "" {
+			for i, f := range s.Frames {
+				if f.Func == from {
+					s.Frames = s.Frames[i:]
+					break
+				}
+			}
+		}
+		m = append(m, F("stack", s))
+	}
+	if st.allEnabled && level >= st.allLevel {
+		m = append(m, F("goroutines", captureGoroutines()))
+	}
+	return m
+}
+
+// Stack is the stack trace of a goroutine.
+//
+// It is encoded as an array of frames in JSON and
+// in the format of Go's panic output as a string.
+type Stack struct {
+	Goroutine int
+	State     string
+	Frames    []Frame
+}
+
+// Frame is a single frame of a Stack.
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+
+	// offset is the offset of the PC from the start of the function.
+	offset uintptr
+}
+
+var (
+	_ json.Marshaler = Stack{}
+	_ fmt.Stringer   = Stack{}
+)
+
+// MarshalJSON implements json.Marshaler.
+func (s Stack) MarshalJSON() ([]byte, error) {
+	if s.Frames == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.Frames)
+}
+
+// String implements fmt.Stringer.
+func (s Stack) String() string {
+	var b strings.Builder
+	s.write(&b)
+	return b.String()
+}
+
+func (s Stack) write(b *strings.Builder) {
+	fmt.Fprintf(b, "goroutine %v [%v]:", s.Goroutine, s.State)
+	for _, f := range s.Frames {
+		if strings.HasPrefix(f.Func, "created by ") {
+			fmt.Fprintf(b, "\n%v\n\t%v:%v", f.Func, f.File, f.Line)
+		} else {
+			fmt.Fprintf(b, "\n%v(...)\n\t%v:%v", f.Func, f.File, f.Line)
+		}
+		if f.offset != 0 {
+			fmt.Fprintf(b, " +%#x", f.offset)
+		}
+	}
+}
+
+// Goroutines are the stacks of all goroutines.
+//
+// It is encoded as an array of objects with the goroutine,
+// state and frames in JSON and in the format of Go's panic
+// output as a string.
+type Goroutines []Stack
+
+var (
+	_ json.Marshaler = Goroutines{}
+	_ fmt.Stringer   = Goroutines{}
+)
+
+// MarshalJSON implements json.Marshaler.
+func (gs Goroutines) MarshalJSON() ([]byte, error) {
+	type goroutine struct {
+		Goroutine int     `json:"goroutine"`
+		State     string  `json:"state"`
+		Frames    []Frame `json:"frames"`
+	}
+	jgs := make([]goroutine, 0, len(gs))
+	for _, g := range gs {
+		jgs = append(jgs, goroutine(g))
+	}
+	return json.Marshal(jgs)
+}
+
+// String implements fmt.Stringer.
+func (gs Goroutines) String() string {
+	var b strings.Builder
+	for i, g := range gs {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		g.write(&b)
+	}
+	return b.String()
+}
+
+// captureStack captures the stack of the calling goroutine
+// skipping skip frames above the caller.
+func captureStack(skip int) Stack {
+	const maxStackLen = 64
+	var pc [maxStackLen]uintptr
+	// Skip two extra frames to account for this function
+	// and runtime.Callers itself.
+	n := runtime.Callers(skip+2, pc[:])
+
+	s := goroutineHeader()
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		s.Frames = append(s.Frames, Frame{
+			Func:   f.Function,
+			File:   f.File,
+			Line:   f.Line,
+			offset: f.PC - f.Entry,
+		})
+		if !more {
+			return s
+		}
+	}
+}
+
+// goroutineHeader returns the ID and state of the calling goroutine.
+func goroutineHeader() Stack {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	line, _, _ := bytes.Cut(buf[:n], []byte("\n"))
+	s, _ := parseGoroutineHeader(string(line))
+	return s
+}
+
+// parseGoroutineHeader parses a header like "goroutine 1 [running]:".
+func parseGoroutineHeader(line string) (Stack, bool) {
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok {
+		return Stack{}, false
+	}
+	id, state, ok := strings.Cut(rest, " ")
+	if !ok {
+		return Stack{}, false
+	}
+	goid, err := strconv.Atoi(id)
+	if err != nil {
+		return Stack{}, false
+	}
+	state = strings.TrimSuffix(state, ":")
+	state = strings.TrimSuffix(strings.TrimPrefix(state, "["), "]")
+	return Stack{Goroutine: goid, State: state}, true
+}
+
+// captureGoroutines captures the stacks of all goroutines
+// by parsing the output of runtime.Stack.
+func captureGoroutines() Goroutines {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	var gs Goroutines
+	for _, block := range strings.Split(strings.TrimSpace(string(buf)), "\n\n") {
+		lines := strings.Split(block, "\n")
+		s, ok := parseGoroutineHeader(lines[0])
+		if !ok {
+			continue
+		}
+		for i := 1; i+1 < len(lines); i += 2 {
+			if strings.HasPrefix(lines[i], "...") {
+				// e.g. "...additional frames elided..."
+				i--
+				continue
+			}
+			s.Frames = append(s.Frames, parseFrame(lines[i], lines[i+1]))
+		}
+		gs = append(gs, s)
+	}
+	return gs
+}
+
+// parseFrame parses a frame of runtime.Stack output such as
+//
+//	main.main()
+//		/src/main.go:10 +0x1d
+func parseFrame(fn, loc string) Frame {
+	var f Frame
+	f.Func = fn
+	if i := strings.LastIndexByte(fn, '('); i > 0 {
+		f.Func = fn[:i]
+	}
+
+	loc = strings.TrimSpace(loc)
+	loc, off, _ := strings.Cut(loc, " +")
+	if i := strings.LastIndexByte(loc, ':'); i >= 0 {
+		f.File = loc[:i]
+		f.Line, _ = strconv.Atoi(loc[i+1:])
+	} else {
+		f.File = loc
+	}
+	if o, err := strconv.ParseUint(off, 0, 64); err == nil {
+		f.offset = uintptr(o)
+	}
+	return f
+}
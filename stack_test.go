@@ -0,0 +1,81 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+func TestStackTraces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stack", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).StackTraces(slog.LevelError)
+		l.Warn(bg, "no stack")
+		l.Error(bg, "stack", slog.F("a", 1))
+
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Len(t, "fields", 0, s.entries[0].Fields)
+		assert.Len(t, "fields", 2, s.entries[1].Fields)
+		assert.Equal(t, "name", "stack", s.entries[1].Fields[1].Name)
+
+		st, ok := s.entries[1].Fields[1].Value.(slog.Stack)
+		assert.True(t, "stack", ok)
+		assert.True(t, "goroutine", st.Goroutine > 0)
+		assert.Equal(t, "state", "running", st.State)
+		assert.Equal(t, "func", "cdr.dev/slog/v3_test.TestStackTraces.func1", st.Frames[0].Func)
+		assert.Equal(t, "file", slogTestFile[:len(slogTestFile)-len("slog_test.go")]+"stack_test.go", st.Frames[0].File)
+		assert.Equal(t, "line", s.entries[1].Line, st.Frames[0].Line)
+
+		b, err := json.Marshal(st)
+		assert.Success(t, "marshal", err)
+		var frames []slog.Frame
+		err = json.Unmarshal(b, &frames)
+		assert.Success(t, "unmarshal", err)
+		assert.Len(t, "frames", len(st.Frames), frames)
+		assert.Equal(t, "func", st.Frames[0].Func, frames[0].Func)
+		assert.Equal(t, "line", st.Frames[0].Line, frames[0].Line)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		st := slog.Stack{
+			Goroutine: 7,
+			State:     "running",
+			Frames: []slog.Frame{
+				{Func: "main.run", File: "/src/main.go", Line: 10},
+				{Func: "main.main", File: "/src/main.go", Line: 4},
+			},
+		}
+		assert.Equal(t, "string", "goroutine 7 [running]:\nmain.run(...)\n\t/src/main.go:10\nmain.main(...)\n\t/src/main.go:4", st.String())
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		s := &fakeSink{}
+		l := slog.Make(s).AllStackTraces(slog.LevelCritical)
+		l.Error(bg, "no goroutines")
+		l.Critical(bg, "goroutines")
+
+		assert.Len(t, "entries", 2, s.entries)
+		assert.Len(t, "fields", 0, s.entries[0].Fields)
+		assert.Len(t, "fields", 1, s.entries[1].Fields)
+		assert.Equal(t, "name", "goroutines", s.entries[1].Fields[0].Name)
+
+		gs, ok := s.entries[1].Fields[0].Value.(slog.Goroutines)
+		assert.True(t, "goroutines", ok)
+		assert.True(t, "count", len(gs) > 1)
+		assert.True(t, "current", strings.Contains(gs.String(), "cdr.dev/slog/v3_test.TestStackTraces.func3(...)"))
+
+		_, err := json.Marshal(gs)
+		assert.Success(t, "marshal", err)
+	})
+}
@@ -0,0 +1,82 @@
+package slog
+
+import "fmt"
+
+// LogValuer is implemented by field values that are
+// expensive to compute.
+//
+// LogValue is only called once the entry it is logged with
+// has passed the level checks. Logger then calls it once per
+// entry before the hooks and sinks see the entry so that every
+// sink writes the same value. If LogValue returns another
+// LogValuer, it is resolved as well.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// LogValuerFunc is a LogValuer that calls the function.
+//
+//	l.Debug(ctx, "state", slog.F("dump", slog.LogValuerFunc(func() interface{} {
+//		return expensiveDump()
+//	})))
+type LogValuerFunc func() interface{}
+
+// LogValue implements LogValuer.
+func (fn LogValuerFunc) LogValue() interface{} {
+	return fn()
+}
+
+// maxLogValues bounds the number of LogValue calls made by
+// ResolveValue to protect against LogValuers returning themselves.
+const maxLogValues = 100
+
+// ResolveValue calls LogValue on v until the result no longer
+// implements LogValuer. Logger resolves the field values of the
+// entries it logs so sinks only need it for entries passed to
+// Sink.LogEntry directly.
+//
+// A panic in LogValue is recovered and returned as an error value.
+func ResolveValue(v interface{}) interface{} {
+	for i := 0; i < maxLogValues; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok {
+			return v
+		}
+		v = logValue(lv)
+	}
+	return fmt.Errorf("LogValue called more than %v times; possible infinite loop", maxLogValues)
+}
+
+func logValue(lv LogValuer) (v interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			v = fmt.Errorf("LogValue panicked: %v", r)
+		}
+	}()
+	return lv.LogValue()
+}
+
+// resolveMap returns m with the LogValuer values resolved,
+// including those in groups. It returns m itself if it
+// has none.
+func resolveMap(m Map) Map {
+	var m2 Map
+	for i, f := range m {
+		v := ResolveValue(f.Value)
+		if gm, ok := v.(Map); ok {
+			v = resolveMap(gm)
+		}
+		if m2 == nil {
+			if sameValue(f.Value, v) {
+				continue
+			}
+			m2 = make(Map, i, len(m))
+			copy(m2, m[:i])
+		}
+		m2 = append(m2, F(f.Name, v))
+	}
+	if m2 == nil {
+		return m
+	}
+	return m2
+}
@@ -0,0 +1,71 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/internal/assert"
+)
+
+type loopValuer struct{}
+
+func (v loopValuer) LogValue() interface{} {
+	return v
+}
+
+func TestResolveValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lazy", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		v := slog.LogValuerFunc(func() interface{} {
+			calls++
+			return slog.LogValuerFunc(func() interface{} {
+				return "resolved"
+			})
+		})
+
+		s := &fakeSink{}
+		s2 := &fakeSink{}
+		l := slog.Make(s, s2).AppendHooks(slog.HookFunc(func(_ context.Context, e slog.SinkEntry) (slog.SinkEntry, bool) {
+			assert.Equal(t, "hook value", "resolved", e.Fields[0].Value)
+			return e, true
+		}))
+		l.Debug(bg, "dropped", slog.F("v", v))
+		assert.Equal(t, "calls", 0, calls)
+
+		l.Info(bg, "logged", slog.F("v", v), slog.Group("g", slog.F("v", v)))
+		assert.Equal(t, "calls", 2, calls)
+		assert.Equal(t, "value", "resolved", s.entries[0].Fields[0].Value)
+		assert.Equal(t, "group", slog.M(slog.F("v", "resolved")), s.entries[0].Fields[1].Value)
+		assert.Equal(t, "value", "resolved", s2.entries[0].Fields[0].Value)
+		assert.Equal(t, "calls", 2, calls)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		v := slog.ResolveValue(slog.LogValuerFunc(func() interface{} {
+			panic("oops")
+		}))
+		err, ok := v.(error)
+		assert.True(t, "error", ok)
+		assert.Equal(t, "error", "LogValue panicked: oops", err.Error())
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := slog.ResolveValue(loopValuer{}).(error)
+		assert.True(t, "error", ok)
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "value", 3, slog.ResolveValue(3))
+	})
+}